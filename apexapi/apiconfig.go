@@ -15,7 +15,12 @@ type Config struct {
 }
 
 type API struct {
-	ApiToken string `yaml:"apitoken"`
+	ApiToken     string   `yaml:"apitoken"`
+	SeasonURL    string   `yaml:"season_url"`    // 可选：远程赛季日历地址，留空则使用 asset/season.yaml
+	PollInterval string   `yaml:"poll_interval"` // 可选：后台轮询已绑定玩家段位的间隔（如 30m），留空则不轮询
+	NewsURL      string   `yaml:"news_url"`      // 可选：新闻 RSS/Atom 地址，留空则使用 apexlegendsstatus 的新闻
+	StatusURL    string   `yaml:"status_url"`    // 可选：服务器状态数据地址，留空则按 providers 的顺序获取
+	Providers    []string `yaml:"providers"`     // 可选：数据来源的尝试顺序（gateway、official），留空则为 gateway, official
 }
 
 var (
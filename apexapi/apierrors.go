@@ -5,40 +5,133 @@ import (
 	"fmt"
 )
 
-var (
-	ErrEmptyURL            = errors.New("提供的URL为空")
-	ErrEmptyAPIToken       = errors.New("未填入API Token")
-	ErrWrongAPIToken       = errors.New("API Token错误")
-	ErrRequestCreateFailed = errors.New("构造请求失败")
-	ErrRequestFailed       = errors.New("请求失败")
-	ErrInvalidJSON         = errors.New("JSON解析错误")
-	Err403Forbidden        = errors.New("API密钥出错[403]")
-	ErrNoPlayerFound       = errors.New("未找到该玩家，请检查名称后重试")
-	ErrReadResponseFailed  = errors.New("读取响应内容失败")
-	ErrAPIReturnedError    = func(msg string) error {
-		return errors.New("API返回错误: " + msg)
-	}
-	ErrUnexpectedStatusCode = func(code int) error {
-		return errors.New("收到意外状态码: " + fmt.Sprint(code))
+// ErrorKind API 错误的类别，用于决定是否重试以及回复给用户的提示
+type ErrorKind int
+
+const (
+	KindUnknown     ErrorKind = iota // 无法归类的错误
+	KindConfig                       // 配置错误（API Token、地址等），需要管理员处理
+	KindNetwork                      // 网络请求失败或读取响应失败
+	KindBadResponse                  // 响应内容无法解析
+	KindNotFound                     // 玩家不存在
+	KindRateLimited                  // 触发 API 速率限制
+	KindUnavailable                  // API 服务器不可用（5xx）
+	KindUpstream                     // API 返回了错误信息
+	KindUnsupported                  // 数据来源不提供该数据
+)
+
+// String 错误类别的名称，用于日志
+func (k ErrorKind) String() string {
+	switch k {
+	case KindConfig:
+		return "config"
+	case KindNetwork:
+		return "network"
+	case KindBadResponse:
+		return "bad_response"
+	case KindNotFound:
+		return "not_found"
+	case KindRateLimited:
+		return "rate_limited"
+	case KindUnavailable:
+		return "unavailable"
+	case KindUpstream:
+		return "upstream"
+	case KindUnsupported:
+		return "unsupported"
+	default:
+		return "unknown"
 	}
-	ErrStatusCode = func(code int) error {
-		switch code {
-		case 400:
-			return ErrAPIReturnedError("API请求出错，请稍后再试吧o.0")
-		case 403:
-			return Err403Forbidden
-		case 404:
-			return ErrNoPlayerFound
-		case 405:
-			return ErrAPIReturnedError("外部API错误，请联系管理员修复>w<")
-		case 410:
-			return ErrAPIReturnedError("未知平台，请联系管理员修复>w<")
-		case 429:
-			return ErrAPIReturnedError("API速率限制，请稍后再试吧X_X")
-		case 503:
-			return ErrAPIReturnedError("似乎API服务器不可用，请稍后再试吧")
-		default:
-			return fmt.Errorf("%w: 状态码%d", ErrUnexpectedStatusCode(code), code)
+}
+
+// APIError 外部 API 调用的错误
+//
+// 预定义的 Err* 变量为各类错误的哨兵值；StatusError、UpstreamError 创建的错误会包裹对应的哨兵值，
+// 因此可以使用 errors.Is 判断具体错误，使用 errors.As 获取类别、状态码等信息。
+// Error() 中可能包含接口返回的原始信息，只应写入日志，回复用户请使用 UserMessage。
+type APIError struct {
+	Kind       ErrorKind
+	StatusCode int    // HTTP 状态码，非 HTTP 错误时为 0
+	Retryable  bool   // 稍后重试是否可能成功
+	Message    string // 哨兵值的描述，或接口返回的原始信息
+	Err        error  // 包裹的哨兵值
+}
+
+func (e *APIError) Error() string {
+	msg := e.Message
+	if e.Err != nil {
+		msg = e.Err.Error()
+		if e.Message != "" {
+			msg += ": " + e.Message
 		}
 	}
+	if e.StatusCode != 0 {
+		msg += fmt.Sprintf("（状态码 %d）", e.StatusCode)
+	}
+	return msg
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+var (
+	ErrEmptyURL            = &APIError{Kind: KindConfig, Message: "提供的URL为空"}
+	ErrEmptyAPIToken       = &APIError{Kind: KindConfig, Message: "未填入API Token"}
+	ErrWrongAPIToken       = &APIError{Kind: KindConfig, Message: "API Token错误"}
+	Err403Forbidden        = &APIError{Kind: KindConfig, Message: "API密钥出错[403]"}
+	ErrRequestCreateFailed = &APIError{Kind: KindConfig, Message: "构造请求失败"}
+	ErrRequestFailed       = &APIError{Kind: KindNetwork, Retryable: true, Message: "请求失败"}
+	ErrReadResponseFailed  = &APIError{Kind: KindNetwork, Retryable: true, Message: "读取响应内容失败"}
+	ErrInvalidJSON         = &APIError{Kind: KindBadResponse, Message: "JSON解析错误"}
+	ErrNoPlayerFound       = &APIError{Kind: KindNotFound, Message: "未找到该玩家，请检查名称后重试"}
+	ErrNotFound            = &APIError{Kind: KindUpstream, Message: "请求的资源不存在"}
+	ErrRateLimited         = &APIError{Kind: KindRateLimited, Retryable: true, Message: "API速率限制"}
+	ErrServiceUnavailable  = &APIError{Kind: KindUnavailable, Retryable: true, Message: "API服务器不可用"}
+	ErrUpstream            = &APIError{Kind: KindUpstream, Message: "API返回错误"}
+	ErrUnexpectedStatus    = &APIError{Kind: KindUnknown, Message: "收到意外状态码"}
+	ErrCircuitOpen         = &APIError{Kind: KindUnavailable, Retryable: true, Message: "上游服务连续失败，暂停请求"}
+	// ErrNotSupported 数据来源不提供该项数据，数据来源链会跳过并尝试下一个
+	ErrNotSupported = &APIError{Kind: KindUnsupported, Message: "数据来源不支持该数据"}
 )
+
+// UpstreamError 接口通过响应内容返回的错误，msg 为接口返回的原始信息
+func UpstreamError(msg string) error {
+	return &APIError{Kind: KindUpstream, Message: msg, Err: ErrUpstream}
+}
+
+// StatusError 根据 HTTP 状态码创建错误
+func StatusError(code int) error {
+	var sentinel *APIError
+	switch {
+	case code == 400, code == 405, code == 410:
+		sentinel = ErrUpstream
+	case code == 401, code == 403:
+		sentinel = Err403Forbidden
+	case code == 404:
+		// 只有玩家接口的 404 表示玩家不存在，由玩家数据的解析处转换为 ErrNoPlayerFound
+		sentinel = ErrNotFound
+	case code == 429:
+		sentinel = ErrRateLimited
+	case code >= 500:
+		sentinel = ErrServiceUnavailable
+	default:
+		sentinel = ErrUnexpectedStatus
+	}
+	return &APIError{Kind: sentinel.Kind, StatusCode: code, Retryable: sentinel.Retryable, Err: sentinel}
+}
+
+// ErrorKindOf 获取错误的类别，非 APIError 时返回 KindUnknown
+func ErrorKindOf(err error) ErrorKind {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Kind
+	}
+	return KindUnknown
+}
+
+// IsRetryable 判断错误是否可能通过稍后重试解决
+func IsRetryable(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Retryable
+}
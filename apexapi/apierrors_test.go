@@ -0,0 +1,75 @@
+package apexapi
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestStatusError(t *testing.T) {
+	tests := []struct {
+		code      int
+		sentinel  error
+		kind      ErrorKind
+		retryable bool
+	}{
+		{400, ErrUpstream, KindUpstream, false},
+		{403, Err403Forbidden, KindConfig, false},
+		{404, ErrNotFound, KindUpstream, false},
+		{429, ErrRateLimited, KindRateLimited, true},
+		{502, ErrServiceUnavailable, KindUnavailable, true},
+		{418, ErrUnexpectedStatus, KindUnknown, false},
+	}
+	for _, tt := range tests {
+		err := fmt.Errorf("获取数据失败: %w", StatusError(tt.code))
+		if !errors.Is(err, tt.sentinel) {
+			t.Errorf("StatusError(%d) 应匹配 %v", tt.code, tt.sentinel)
+		}
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.code || apiErr.Kind != tt.kind {
+			t.Errorf("StatusError(%d) = %+v", tt.code, apiErr)
+		}
+		if IsRetryable(err) != tt.retryable {
+			t.Errorf("IsRetryable(%d) = %v, want %v", tt.code, !tt.retryable, tt.retryable)
+		}
+	}
+	if errors.Is(StatusError(404), ErrRateLimited) {
+		t.Error("404 不应匹配速率限制")
+	}
+	// 404 只在玩家接口中表示玩家不存在
+	if errors.Is(StatusError(404), ErrNoPlayerFound) {
+		t.Error("通用的 404 不应匹配玩家不存在")
+	}
+	if err := playerLookupError(StatusError(404)); !errors.Is(err, ErrNoPlayerFound) || ErrorKindOf(err) != KindNotFound {
+		t.Errorf("玩家接口的 404 = %v", err)
+	}
+	if err := playerLookupError(StatusError(503)); !errors.Is(err, ErrServiceUnavailable) {
+		t.Errorf("玩家接口的其他错误应保持不变: %v", err)
+	}
+}
+
+func TestUserMessageHidesDetails(t *testing.T) {
+	upstream := fmt.Errorf("请求 https://api.example.com/?auth=secret 失败: %w", UpstreamError("internal trace"))
+	if !errors.Is(upstream, ErrUpstream) || ErrorKindOf(upstream) != KindUpstream {
+		t.Fatalf("UpstreamError 未能识别: %v", upstream)
+	}
+	if !strings.Contains(upstream.Error(), "internal trace") {
+		t.Errorf("日志中应包含原始信息: %q", upstream.Error())
+	}
+
+	for _, err := range []error{
+		upstream,
+		fmt.Errorf("%w: dial tcp 1.2.3.4:443", ErrRequestFailed),
+		errors.New("open /srv/asset/season.yaml: no such file"),
+	} {
+		msg := UserMessage(err)
+		if msg == "" || strings.Contains(msg, "http") || strings.Contains(msg, "secret") ||
+			strings.Contains(msg, "internal") || strings.Contains(msg, "/srv") || strings.Contains(msg, "1.2.3.4") {
+			t.Errorf("UserMessage(%v) = %q", err, msg)
+		}
+	}
+	if got := UserMessage(ErrNoPlayerFound); !strings.Contains(got, "未找到该玩家") {
+		t.Errorf("UserMessage(ErrNoPlayerFound) = %q", got)
+	}
+}
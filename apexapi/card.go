@@ -0,0 +1,13 @@
+package apexapi
+
+import "image/color"
+
+// 卡片类图片（区服表、服务器状态、制造器轮换等）共用的配色
+var (
+	cardBackground     = color.RGBA{30, 30, 40, 255}
+	cardStripe         = color.RGBA{40, 40, 52, 255}
+	cardLabelColor     = color.RGBA{200, 200, 200, 255}
+	cardValueColor     = color.RGBA{230, 230, 230, 255}
+	cardHighlightColor = color.RGBA{255, 200, 100, 255}
+	cardDimColor       = color.RGBA{150, 150, 150, 255}
+)
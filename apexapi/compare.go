@@ -0,0 +1,238 @@
+package apexapi
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"sync"
+
+	"github.com/newton-miku/apexQQbot/tools"
+)
+
+const (
+	compareImageWidth   = 960
+	compareHeaderHeight = 120
+	compareRowHeight    = 56
+	compareFooterHeight = 20
+	// 对比卡片中最多展示的传奇追踪器行数
+	compareMaxTrackerRows = 12
+)
+
+var (
+	compareBackground = color.RGBA{30, 30, 40, 255}
+	compareStripe     = color.RGBA{40, 40, 52, 255}
+	compareLabelColor = color.RGBA{200, 200, 200, 255}
+	compareValueColor = color.RGBA{230, 230, 230, 255}
+	compareWinColor   = color.RGBA{255, 200, 100, 255}
+	compareLoseColor  = color.RGBA{150, 150, 150, 255}
+	compareBarBack    = color.RGBA{70, 70, 85, 255}
+	compareBarFill    = color.RGBA{120, 200, 255, 255}
+)
+
+// compareRow 对比卡片中的一行
+type compareRow struct {
+	label       string
+	left, right string
+	// 数值可比较时用于高亮较大的一方
+	leftVal, rightVal float64
+	comparable        bool
+	// 进度条行：left/right 为说明文字，ratio 为进度
+	bar                   bool
+	leftRatio, rightRatio float64
+}
+
+// FetchPlayersConcurrently 并发获取两名玩家的数据
+func FetchPlayersConcurrently(ctx context.Context, eaid1, eaid2 string) (*PlayerResponse, *PlayerResponse, error) {
+	eaids := [2]string{eaid1, eaid2}
+	var (
+		wg      sync.WaitGroup
+		players [2]*PlayerResponse
+		errs    [2]error
+	)
+	for i := range eaids {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			players[i], errs[i] = GetPlayerData(ctx, eaids[i])
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, nil, fmt.Errorf("获取 %s 的数据失败: %w", eaids[i], err)
+		}
+		if players[i] == nil {
+			return nil, nil, fmt.Errorf("获取到 %s 的空数据", eaids[i])
+		}
+	}
+	return players[0], players[1], nil
+}
+
+// buildCompareRows 生成对比卡片的各行：等级、段位、段位分数、段位进度以及双方共有的传奇追踪器
+func buildCompareRows(a, b *PlayerResponse) []compareRow {
+	scoreA, scoreB := a.Global.Rank.RankScore, b.Global.Rank.RankScore
+	rows := []compareRow{
+		{
+			label: "等级",
+			left:  FormatNumber(a.Global.Level), right: FormatNumber(b.Global.Level),
+			leftVal: a.Global.Level, rightVal: b.Global.Level, comparable: true,
+		},
+		{
+			label: "段位",
+			left:  formatRankName(a.Global.Rank), right: formatRankName(b.Global.Rank),
+			leftVal: scoreA, rightVal: scoreB, comparable: true,
+		},
+		{
+			label: "段位分数",
+			left:  FormatNumber(scoreA), right: FormatNumber(scoreB),
+			leftVal: scoreA, rightVal: scoreB, comparable: true,
+		},
+	}
+	if ladder, err := GetRankLadder(); err == nil {
+		progressA, okA := ladder.Progress(int(scoreA))
+		progressB, okB := ladder.Progress(int(scoreB))
+		if okA && okB {
+			rows = append(rows, compareRow{
+				label: "段位进度",
+				left:  formatProgressHint(progressA, int(scoreA)), right: formatProgressHint(progressB, int(scoreB)),
+				bar:       true,
+				leftRatio: progressA.Ratio(int(scoreA)), rightRatio: progressB.Ratio(int(scoreB)),
+			})
+		}
+	}
+
+	// 双方都装备了的传奇追踪器
+	trackerRows := 0
+	for _, legend := range a.LegendNames() {
+		dataB := b.LegendData(legend)
+		if len(dataB) == 0 {
+			continue
+		}
+		for _, statA := range a.LegendData(legend) {
+			statB, ok := findStat(dataB, statA)
+			if !ok {
+				continue
+			}
+			valA, okA := statA.Number()
+			valB, okB := statB.Number()
+			rows = append(rows, compareRow{
+				label: fmt.Sprintf("%s·%s", GetLegendName(legend), statA.DisplayName()),
+				left:  statA.DisplayValue(), right: statB.DisplayValue(),
+				leftVal: valA, rightVal: valB, comparable: okA && okB,
+			})
+			trackerRows++
+			if trackerRows >= compareMaxTrackerRows {
+				return rows
+			}
+		}
+	}
+	return rows
+}
+
+// findStat 在数据项列表中查找与 target 相同的追踪器（优先比较 key）
+func findStat(items []LegendStatItem, target LegendStatItem) (LegendStatItem, bool) {
+	for _, item := range items {
+		if target.Key != "" && item.Key == target.Key {
+			return item, true
+		}
+		if target.Key == "" && item.Name == target.Name {
+			return item, true
+		}
+	}
+	return LegendStatItem{}, false
+}
+
+// formatRankName 段位的展示名，如“钻石 4”
+func formatRankName(rank RankInfo) string {
+	if rank.RankName == "" {
+		return "未定级"
+	}
+	if rank.RankDiv <= 0 {
+		return GetRankTierName(rank.RankName)
+	}
+	return fmt.Sprintf("%s %d", GetRankTierName(rank.RankName), rank.RankDiv)
+}
+
+// formatProgressHint 进度条下方的说明文字
+func formatProgressHint(progress RankProgress, score int) string {
+	if progress.Next == nil {
+		return "已达最高段位"
+	}
+	return fmt.Sprintf("距 %s 差 %d", progress.Next, progress.Remaining(score))
+}
+
+// RenderCompareImage 绘制两名玩家的对比卡片，较高的数值以高亮色显示
+func RenderCompareImage(a, b *PlayerResponse) ([]byte, error) {
+	if a == nil || b == nil {
+		return nil, fmt.Errorf("玩家数据为空")
+	}
+	fontPath, err := getMapFontPath()
+	if err != nil {
+		return nil, err
+	}
+
+	rows := buildCompareRows(a, b)
+	height := compareHeaderHeight + len(rows)*compareRowHeight + compareFooterHeight
+	dst := image.NewRGBA(image.Rect(0, 0, compareImageWidth, height))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(compareBackground), image.Point{}, draw.Src)
+
+	const leftX, rightX, centerX = compareImageWidth / 5, compareImageWidth * 4 / 5, compareImageWidth / 2
+	lines := []tools.TextLine{
+		{Text: a.Global.Name, Size: 40, X: leftX, Y: 70, Alignment: "center", FontPath: fontPath, Color: compareValueColor},
+		{Text: "VS", Size: 48, X: centerX, Y: 75, Alignment: "center", FontPath: fontPath, Color: compareWinColor},
+		{Text: b.Global.Name, Size: 40, X: rightX, Y: 70, Alignment: "center", FontPath: fontPath, Color: compareValueColor},
+	}
+
+	for i, row := range rows {
+		top := compareHeaderHeight + i*compareRowHeight
+		if i%2 == 0 {
+			draw.Draw(dst, image.Rect(0, top, compareImageWidth, top+compareRowHeight), image.NewUniform(compareStripe), image.Point{}, draw.Src)
+		}
+
+		leftColor, rightColor := compareValueColor, compareValueColor
+		if row.comparable && row.leftVal != row.rightVal {
+			leftColor, rightColor = compareLoseColor, compareLoseColor
+			if row.leftVal > row.rightVal {
+				leftColor = compareWinColor
+			} else {
+				rightColor = compareWinColor
+			}
+		}
+
+		lines = append(lines, tools.TextLine{Text: row.label, Size: 24, X: centerX, Y: top + 36, Alignment: "center", FontPath: fontPath, Color: compareLabelColor})
+		if row.bar {
+			drawCompareBar(dst, leftX, top, row.leftRatio)
+			drawCompareBar(dst, rightX, top, row.rightRatio)
+			lines = append(lines,
+				tools.TextLine{Text: row.left, Size: 18, X: leftX, Y: top + 48, Alignment: "center", FontPath: fontPath, Color: compareLabelColor},
+				tools.TextLine{Text: row.right, Size: 18, X: rightX, Y: top + 48, Alignment: "center", FontPath: fontPath, Color: compareLabelColor},
+			)
+			continue
+		}
+		lines = append(lines,
+			tools.TextLine{Text: row.left, Size: 30, X: leftX, Y: top + 38, Alignment: "center", FontPath: fontPath, Color: leftColor},
+			tools.TextLine{Text: row.right, Size: 30, X: rightX, Y: top + 38, Alignment: "center", FontPath: fontPath, Color: rightColor},
+		)
+	}
+	tools.AddTextToImageInPlace(dst, lines)
+
+	data, err := encodeJPEG(dst)
+	if err != nil {
+		return nil, fmt.Errorf("编码图片失败: %w", err)
+	}
+	return data, nil
+}
+
+// drawCompareBar 在行内绘制以 centerX 为中心的进度条
+func drawCompareBar(dst *image.RGBA, centerX, top int, ratio float64) {
+	const barWidth, barHeight = 240, 14
+	x0, y0 := centerX-barWidth/2, top+12
+	draw.Draw(dst, image.Rect(x0, y0, x0+barWidth, y0+barHeight), image.NewUniform(compareBarBack), image.Point{}, draw.Src)
+	filled := int(min(max(ratio, 0), 1) * barWidth)
+	if filled > 0 {
+		draw.Draw(dst, image.Rect(x0, y0, x0+filled, y0+barHeight), image.NewUniform(compareBarFill), image.Point{}, draw.Src)
+	}
+}
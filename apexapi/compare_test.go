@@ -0,0 +1,57 @@
+package apexapi
+
+import (
+	"bytes"
+	"image/jpeg"
+	"testing"
+)
+
+func TestCompareImage(t *testing.T) {
+	a, err := parsePlayerResponse([]byte(samplePlayerJSON))
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	b, err := parsePlayerResponse([]byte(`{
+		"global": {"name": "Rival", "uid": 1, "level": 300, "rank": {"rankName": "Platinum", "rankDiv": 1, "rankScore": 10500}},
+		"legends": {"all": {
+			"Wraith": {"data": [{"name": "BR Kills", "value": 500, "key": "kills"}, {"name": "BR Wins", "value": 20, "key": "wins"}]},
+			"Lifeline": {"data": [{"name": "Revives", "value": 30, "key": "revives"}]}
+		}}
+	}`))
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+
+	rows := buildCompareRows(a, b)
+	var tracker *compareRow
+	for i := range rows {
+		if rows[i].left == "321" {
+			tracker = &rows[i]
+		}
+	}
+	if tracker == nil {
+		t.Fatalf("缺少共有的追踪器行: %+v", rows)
+	}
+	if !tracker.comparable || tracker.rightVal != 500 {
+		t.Errorf("追踪器行数据错误: %+v", *tracker)
+	}
+	// 只有一方装备的追踪器不参与对比
+	for _, row := range rows {
+		if row.right == "20" || row.right == "30" {
+			t.Errorf("不应包含单方独有的追踪器: %+v", row)
+		}
+	}
+
+	data, err := RenderCompareImage(a, b)
+	if err != nil {
+		t.Fatalf("绘制对比图失败: %v", err)
+	}
+	img, err := jpeg.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("解码对比图失败: %v", err)
+	}
+	wantHeight := compareHeaderHeight + len(rows)*compareRowHeight + compareFooterHeight
+	if bounds := img.Bounds(); bounds.Dx() != compareImageWidth || bounds.Dy() != wantHeight {
+		t.Errorf("对比图尺寸为 %v，期望 %dx%d", bounds.Size(), compareImageWidth, wantHeight)
+	}
+}
@@ -0,0 +1,318 @@
+package apexapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/newton-miku/apexQQbot/tools"
+	botlog "github.com/tencent-connect/botgo/log"
+)
+
+// 制造器图片布局
+const (
+	craftingImageWidth   = 960
+	craftingHeaderHeight = 90
+	craftingSectionTitle = 60
+	craftingColumns      = 4
+	craftingCellHeight   = 250
+	craftingIconSize     = 150
+)
+
+// 无法从接口确定过期时间时的缓存时长
+const craftingFallbackTTL = time.Hour
+
+// CraftingItemType 制造器物品信息
+type CraftingItemType struct {
+	Name      string `json:"name"`
+	Rarity    string `json:"rarity"`
+	Asset     string `json:"asset"`
+	RarityHex string `json:"rarityHex"`
+}
+
+// CraftingItem 制造器中的一个物品
+type CraftingItem struct {
+	Item     string           `json:"item"`
+	Cost     int              `json:"cost"`
+	ItemType CraftingItemType `json:"itemType"`
+}
+
+// CraftingBundle 一组制造器物品（每日、每周或常驻）
+type CraftingBundle struct {
+	Bundle        string         `json:"bundle"`
+	BundleType    string         `json:"bundleType"` // daily / weekly / permanent
+	Start         UnixTime       `json:"start"`
+	End           UnixTime       `json:"end"`
+	BundleContent []CraftingItem `json:"bundleContent"`
+}
+
+// Crafting 制造器轮换
+type Crafting []CraftingBundle
+
+// 物品组的展示顺序
+var craftingBundleTypeOrder = []string{"daily", "weekly", "permanent"}
+
+var (
+	cachedCrafting         Crafting
+	craftingCacheExpiresAt time.Time
+	craftingCacheLock      sync.RWMutex
+	itemTranslator         *tools.Translator
+	itemTranslatorOnce     sync.Once
+)
+
+// bundleTypeName 物品组类型的中文名
+func bundleTypeName(bundleType string) string {
+	switch bundleType {
+	case "daily":
+		return "每日轮换"
+	case "weekly":
+		return "每周轮换"
+	case "permanent":
+		return "常驻"
+	default:
+		return bundleType
+	}
+}
+
+// getItemTranslator 获取物品名翻译器（修改 asset/item_dict.json 后自动生效）
+func getItemTranslator() *tools.Translator {
+	itemTranslatorOnce.Do(func() {
+		assetDir, err := GetAssetPath()
+		if err != nil {
+			return
+		}
+		trans, err := tools.NewTranslator(filepath.Join(assetDir, "item_dict.json"))
+		if err != nil {
+			// 静默失败，使用物品代码
+			return
+		}
+		itemTranslator = trans
+	})
+	return itemTranslator
+}
+
+// GetItemName 获取物品中文名，未配置时返回物品代码
+func GetItemName(item string) string {
+	if trans := getItemTranslator(); trans != nil {
+		if name, ok := trans.Lookup(item); ok {
+			return name
+		}
+	}
+	return item
+}
+
+// GetCraftingEarliestEndTime 获取尚未结束的最早轮换结束时间
+func GetCraftingEarliestEndTime(c Crafting) time.Time {
+	now := time.Now()
+	var earliest time.Time
+	for _, bundle := range c {
+		t := time.Time(bundle.End)
+		if !t.After(now) {
+			// 常驻物品没有结束时间
+			continue
+		}
+		if earliest.IsZero() || t.Before(earliest) {
+			earliest = t
+		}
+	}
+	return earliest
+}
+
+// Ordered 按每日、每周、常驻的顺序返回物品组
+func (c Crafting) Ordered() []CraftingBundle {
+	result := make([]CraftingBundle, 0, len(c))
+	for _, bundleType := range craftingBundleTypeOrder {
+		for _, bundle := range c {
+			if bundle.BundleType == bundleType && len(bundle.BundleContent) > 0 {
+				result = append(result, bundle)
+			}
+		}
+	}
+	for _, bundle := range c {
+		if !slices.Contains(craftingBundleTypeOrder, bundle.BundleType) && len(bundle.BundleContent) > 0 {
+			result = append(result, bundle)
+		}
+	}
+	return result
+}
+
+// GetCrafting 获取制造器轮换（带缓存，轮换结束前不重复请求）
+func GetCrafting() (Crafting, error) {
+	craftingCacheLock.RLock()
+	if !craftingCacheExpiresAt.IsZero() && time.Now().Before(craftingCacheExpiresAt) {
+		result := cachedCrafting
+		craftingCacheLock.RUnlock()
+		return result, nil
+	}
+	craftingCacheLock.RUnlock()
+
+	return GetCraftingFromAPI()
+}
+
+// GetCraftingFromAPI 从数据来源获取制造器轮换（不带缓存）
+func GetCraftingFromAPI() (Crafting, error) {
+	crafting, err := GetStatsProvider().Crafting(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := GetCraftingEarliestEndTime(crafting)
+	if expiresAt.IsZero() {
+		expiresAt = time.Now().Add(craftingFallbackTTL)
+	}
+
+	craftingCacheLock.Lock()
+	cachedCrafting = crafting
+	craftingCacheExpiresAt = expiresAt
+	craftingCacheLock.Unlock()
+
+	return crafting, nil
+}
+
+// parseCrafting 解析制造器数据，兼容直接返回数组与包裹在 crafting 字段中的两种格式
+func parseCrafting(body []byte) (Crafting, error) {
+	var crafting Crafting
+	if err := json.Unmarshal(body, &crafting); err == nil {
+		return crafting, nil
+	}
+	var wrapped struct {
+		Crafting Crafting `json:"crafting"`
+	}
+	if err := json.Unmarshal(body, &wrapped); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidJSON, err)
+	}
+	if len(wrapped.Crafting) == 0 {
+		return nil, fmt.Errorf("%w: 制造器数据为空", ErrInvalidJSON)
+	}
+	return wrapped.Crafting, nil
+}
+
+// parseRarityColor 解析稀有度颜色（如 #51a8d6），失败时返回灰色
+func parseRarityColor(hex string) color.RGBA {
+	hex = strings.TrimPrefix(hex, "#")
+	if v, err := strconv.ParseUint(hex, 16, 32); err == nil && len(hex) == 6 {
+		return color.RGBA{uint8(v >> 16), uint8(v >> 8), uint8(v), 255}
+	}
+	return color.RGBA{120, 120, 130, 255}
+}
+
+// craftingImageHeight 计算图片高度
+func craftingImageHeight(bundles []CraftingBundle) int {
+	height := craftingHeaderHeight
+	for _, bundle := range bundles {
+		rows := (len(bundle.BundleContent) + craftingColumns - 1) / craftingColumns
+		height += craftingSectionTitle + rows*craftingCellHeight
+	}
+	return height
+}
+
+// loadCraftingIcon 通过 CacheImage 缓存并读取物品图标，失败时返回 nil
+func loadCraftingIcon(asset string) image.Image {
+	if asset == "" {
+		return nil
+	}
+	path, err := CacheImage(asset)
+	if err != nil {
+		botlog.Warnf("缓存物品图标失败: %v", err)
+		return nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	if err != nil {
+		botlog.Warnf("解码物品图标失败: %v", err)
+		return nil
+	}
+	return ResizeImage(img, craftingIconSize, craftingIconSize, true)
+}
+
+// RenderCraftingImage 将制造器物品按每日、每周、常驻分组绘制为网格图片
+func RenderCraftingImage(c Crafting) ([]byte, error) {
+	bundles := c.Ordered()
+	if len(bundles) == 0 {
+		return nil, fmt.Errorf("当前没有制造器数据")
+	}
+	fontPath, err := getMapFontPath()
+	if err != nil {
+		return nil, err
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, craftingImageWidth, craftingImageHeight(bundles)))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(cardBackground), image.Point{}, draw.Src)
+
+	lines := []tools.TextLine{
+		{Text: "制造器轮换", Size: 44, X: 30, Y: 60, FontPath: fontPath, Color: cardValueColor},
+	}
+	if end := GetCraftingEarliestEndTime(c); !end.IsZero() {
+		lines = append(lines, tools.TextLine{
+			Text: fmt.Sprintf("下次刷新：%s", FormatDuration(time.Until(end))), Size: 30,
+			X: craftingImageWidth - 30, Y: 58, Alignment: "right", FontPath: fontPath, Color: cardHighlightColor,
+		})
+	}
+
+	cellWidth := craftingImageWidth / craftingColumns
+	top := craftingHeaderHeight
+	for _, bundle := range bundles {
+		title := bundleTypeName(bundle.BundleType)
+		if end := time.Time(bundle.End); end.After(time.Now()) {
+			title += fmt.Sprintf("（%s 结束）", end.Local().Format("01-02 15:04"))
+		}
+		draw.Draw(dst, image.Rect(0, top, craftingImageWidth, top+craftingSectionTitle), image.NewUniform(cardStripe), image.Point{}, draw.Src)
+		lines = append(lines, tools.TextLine{Text: title, Size: 32, X: 30, Y: top + 43, FontPath: fontPath, Color: cardLabelColor})
+		top += craftingSectionTitle
+
+		for i, item := range bundle.BundleContent {
+			x := (i % craftingColumns) * cellWidth
+			y := top + (i/craftingColumns)*craftingCellHeight
+			centerX := x + cellWidth/2
+
+			// 稀有度色块作为图标背景
+			iconRect := image.Rect(centerX-craftingIconSize/2-5, y+15, centerX+craftingIconSize/2+5, y+25+craftingIconSize)
+			draw.Draw(dst, iconRect, image.NewUniform(parseRarityColor(item.ItemType.RarityHex)), image.Point{}, draw.Src)
+			if icon := loadCraftingIcon(item.ItemType.Asset); icon != nil {
+				b := icon.Bounds()
+				offset := image.Pt(centerX-b.Dx()/2, y+20+(craftingIconSize-b.Dy())/2)
+				draw.Draw(dst, b.Add(offset), icon, b.Min, draw.Over)
+			}
+
+			name := item.ItemType.Name
+			if name == "" {
+				name = item.Item
+			}
+			lines = append(lines,
+				tools.TextLine{Text: GetItemName(name), Size: 24, X: centerX, Y: y + craftingIconSize + 60, Alignment: "center", FontPath: fontPath, Color: cardValueColor},
+				tools.TextLine{Text: fmt.Sprintf("材料 %d", item.Cost), Size: 22, X: centerX, Y: y + craftingIconSize + 90, Alignment: "center", FontPath: fontPath, Color: cardHighlightColor},
+			)
+		}
+		top += (len(bundle.BundleContent) + craftingColumns - 1) / craftingColumns * craftingCellHeight
+	}
+	tools.AddTextToImageInPlace(dst, lines)
+
+	data, err := encodeJPEG(dst)
+	if err != nil {
+		return nil, fmt.Errorf("编码图片失败: %w", err)
+	}
+	return data, nil
+}
+
+// GetCraftingImage 获取当前制造器轮换的图片
+func GetCraftingImage() ([]byte, error) {
+	crafting, err := GetCrafting()
+	if err != nil {
+		return nil, fmt.Errorf("获取制造器轮换失败: %w", err)
+	}
+	return RenderCraftingImage(crafting)
+}
@@ -0,0 +1,59 @@
+package apexapi
+
+import (
+	"bytes"
+	"fmt"
+	"image/jpeg"
+	"testing"
+	"time"
+)
+
+func TestCraftingParseAndRender(t *testing.T) {
+	now := time.Now()
+	daily := now.Add(3 * time.Hour).Unix()
+	weekly := now.Add(72 * time.Hour).Unix()
+	body := fmt.Sprintf(`[
+		{"bundle": "permanent", "bundleType": "permanent", "start": 0, "end": 0,
+			"bundleContent": [{"item": "evo_shield", "cost": 50, "itemType": {"name": "evo_shield", "rarity": "Legendary", "rarityHex": "#d9b739"}}]},
+		{"bundle": "weekly", "bundleType": "weekly", "start": 0, "end": %d,
+			"bundleContent": [{"item": "backpack", "cost": 30, "itemType": {"name": "backpack", "rarityHex": "#51a8d6"}}]},
+		{"bundle": "daily", "bundleType": "daily", "start": 0, "end": %d,
+			"bundleContent": [
+				{"item": "extended_light_mag", "cost": 25, "itemType": {"name": "extended_light_mag", "rarityHex": "#b237c8"}},
+				{"item": "turbocharger", "cost": 35, "itemType": {"name": "turbocharger", "rarityHex": "bad"}}
+			]}
+	]`, weekly, daily)
+
+	crafting, err := parseCrafting([]byte(body))
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	if _, err := parseCrafting([]byte(fmt.Sprintf(`{"crafting": %s}`, body))); err != nil {
+		t.Errorf("解析包裹格式失败: %v", err)
+	}
+
+	if end := GetCraftingEarliestEndTime(crafting); end.Unix() != daily {
+		t.Errorf("最早结束时间为 %v，期望每日轮换的结束时间", end)
+	}
+
+	ordered := crafting.Ordered()
+	if len(ordered) != 3 || ordered[0].BundleType != "daily" || ordered[2].BundleType != "permanent" {
+		t.Fatalf("物品组顺序错误: %+v", ordered)
+	}
+
+	if got := GetItemName("turbocharger"); got != "涡轮增压器" {
+		t.Errorf("物品名翻译错误: %s", got)
+	}
+
+	data, err := RenderCraftingImage(crafting)
+	if err != nil {
+		t.Fatalf("绘制失败: %v", err)
+	}
+	img, err := jpeg.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("解码失败: %v", err)
+	}
+	if want := craftingHeaderHeight + 3*(craftingSectionTitle+craftingCellHeight); img.Bounds().Dy() != want {
+		t.Errorf("图片高度为 %d，期望 %d", img.Bounds().Dy(), want)
+	}
+}
@@ -0,0 +1,33 @@
+package apexapi
+
+import "errors"
+
+// 未知错误的通用提示
+const unknownErrorMessage = "处理失败了，请稍后再试吧"
+
+// 各类 API 错误回复给用户的提示
+var errorKindMessages = map[ErrorKind]string{
+	KindConfig:      "机器人的数据接口配置有误，请联系管理员修复>w<",
+	KindNetwork:     "连接数据服务器失败，请稍后再试吧",
+	KindBadResponse: "数据服务器返回了无法识别的内容，请稍后再试吧o.0",
+	KindNotFound:    "未找到该玩家，请检查名称后重试（需使用EA平台的用户名）",
+	KindRateLimited: "API速率限制，请稍后再试吧X_X",
+	KindUnavailable: "似乎API服务器不可用，请稍后再试吧",
+	KindUpstream:    "API请求出错，请稍后再试吧o.0",
+	KindUnsupported: "当前的数据来源暂不支持该数据，请联系管理员",
+}
+
+// UserMessage 将错误转换为回复给用户的中文提示，不包含接口地址、原始响应等内部信息
+func UserMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return unknownErrorMessage
+	}
+	if msg, ok := errorKindMessages[apiErr.Kind]; ok {
+		return msg
+	}
+	return unknownErrorMessage
+}
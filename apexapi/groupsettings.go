@@ -0,0 +1,244 @@
+package apexapi
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// 群设置项
+const (
+	GroupSettingMapModes     = "map_modes"     // 地图轮换图片中展示的模式，逗号分隔
+	GroupSettingRankAnnounce = "rank_announce" // 是否在群内播报成员段位变化，"1" 为开启
+	GroupSettingNews         = "news"          // 是否订阅新闻推送，"1" 为开启
+)
+
+// GroupSettingData 群设置存储，与玩家绑定数据共用同一个数据库
+type GroupSettingData struct {
+	players *PlayerData
+}
+
+var GroupSettings = GroupSettingData{players: &Players}
+
+// Get 获取群设置
+func (g GroupSettingData) Get(groupID, key string) (string, bool) {
+	p := g.players
+	p.Lock.RLock()
+	defer p.Lock.RUnlock()
+
+	if err := p.ensureInit(); err != nil {
+		return "", false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var value string
+	err := p.db.QueryRowContext(ctx, `
+		SELECT value FROM group_settings WHERE group_id = ? AND setting_key = ?
+	`, groupID, key).Scan(&value)
+	if err == sql.ErrNoRows {
+		return "", false
+	}
+	if err != nil {
+		return "", false
+	}
+	return value, true
+}
+
+// Set 保存群设置
+func (g GroupSettingData) Set(groupID, key, value string) error {
+	p := g.players
+	p.Lock.Lock()
+	defer p.Lock.Unlock()
+
+	if err := p.ensureInit(); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := p.db.ExecContext(ctx, `
+		INSERT OR REPLACE INTO group_settings (group_id, setting_key, value)
+		VALUES (?, ?, ?)
+	`, groupID, key, value)
+	return err
+}
+
+// Delete 删除群设置（恢复默认）
+func (g GroupSettingData) Delete(groupID, key string) error {
+	p := g.players
+	p.Lock.Lock()
+	defer p.Lock.Unlock()
+
+	if err := p.ensureInit(); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := p.db.ExecContext(ctx, "DELETE FROM group_settings WHERE group_id = ? AND setting_key = ?", groupID, key)
+	return err
+}
+
+// GetMapModes 获取群选择的地图模式，未设置时返回 nil（表示全部模式）
+func (g GroupSettingData) GetMapModes(groupID string) []string {
+	value, ok := g.Get(groupID, GroupSettingMapModes)
+	if !ok || value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+// SetMapModes 保存群选择的地图模式，modes 为空时恢复为全部模式
+func (g GroupSettingData) SetMapModes(groupID string, modes []string) error {
+	if len(modes) == 0 {
+		return g.Delete(groupID, GroupSettingMapModes)
+	}
+	return g.Set(groupID, GroupSettingMapModes, strings.Join(modes, ","))
+}
+
+// RankAnnounceEnabled 群是否开启了段位变化播报（默认关闭）
+func (g GroupSettingData) RankAnnounceEnabled(groupID string) bool {
+	value, _ := g.Get(groupID, GroupSettingRankAnnounce)
+	return value == "1"
+}
+
+// SetRankAnnounce 开启或关闭群内段位变化播报
+func (g GroupSettingData) SetRankAnnounce(groupID string, enabled bool) error {
+	if !enabled {
+		return g.Delete(groupID, GroupSettingRankAnnounce)
+	}
+	return g.Set(groupID, GroupSettingRankAnnounce, "1")
+}
+
+// GroupsWith 获取某项设置为指定值的全部群
+func (g GroupSettingData) GroupsWith(key, value string) ([]string, error) {
+	p := g.players
+	p.Lock.RLock()
+	defer p.Lock.RUnlock()
+
+	if err := p.ensureInit(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rows, err := p.db.QueryContext(ctx, `
+		SELECT group_id FROM group_settings WHERE setting_key = ? AND value = ?
+	`, key, value)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var groups []string
+	for rows.Next() {
+		var groupID string
+		if err := rows.Scan(&groupID); err != nil {
+			return nil, err
+		}
+		groups = append(groups, groupID)
+	}
+	return groups, rows.Err()
+}
+
+// NewsSubscribed 群是否订阅了新闻推送
+func (g GroupSettingData) NewsSubscribed(groupID string) bool {
+	value, _ := g.Get(groupID, GroupSettingNews)
+	return value == "1"
+}
+
+// SetNewsSubscribed 订阅或取消订阅新闻推送
+func (g GroupSettingData) SetNewsSubscribed(groupID string, subscribed bool) error {
+	if !subscribed {
+		return g.Delete(groupID, GroupSettingNews)
+	}
+	return g.Set(groupID, GroupSettingNews, "1")
+}
+
+// RecordMember 记录成员在群内使用过机器人，用于确定段位播报的目标群
+func (g GroupSettingData) RecordMember(groupID, qqID string) error {
+	p := g.players
+	p.Lock.Lock()
+	defer p.Lock.Unlock()
+
+	if err := p.ensureInit(); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := p.db.ExecContext(ctx, `
+		INSERT OR REPLACE INTO group_members (group_id, qq_id, last_seen)
+		VALUES (?, ?, ?)
+	`, groupID, qqID, time.Now().Unix())
+	return err
+}
+
+// MemberGroups 获取成员使用过机器人的群
+func (g GroupSettingData) MemberGroups(qqID string) ([]string, error) {
+	p := g.players
+	p.Lock.RLock()
+	defer p.Lock.RUnlock()
+
+	if err := p.ensureInit(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rows, err := p.db.QueryContext(ctx, "SELECT group_id FROM group_members WHERE qq_id = ?", qqID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var groups []string
+	for rows.Next() {
+		var groupID string
+		if err := rows.Scan(&groupID); err != nil {
+			return nil, err
+		}
+		groups = append(groups, groupID)
+	}
+	return groups, rows.Err()
+}
+
+// GroupMembers 获取在群内使用过机器人的成员（按最近使用时间倒序）
+func (g GroupSettingData) GroupMembers(groupID string) ([]string, error) {
+	p := g.players
+	p.Lock.RLock()
+	defer p.Lock.RUnlock()
+
+	if err := p.ensureInit(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rows, err := p.db.QueryContext(ctx, `
+		SELECT qq_id FROM group_members WHERE group_id = ? ORDER BY last_seen DESC
+	`, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var members []string
+	for rows.Next() {
+		var qqID string
+		if err := rows.Scan(&qqID); err != nil {
+			return nil, err
+		}
+		members = append(members, qqID)
+	}
+	return members, rows.Err()
+}
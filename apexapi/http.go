@@ -9,16 +9,30 @@ import (
 // ============ HTTP Client 复用 ============
 
 var (
-	httpClient     *http.Client
-	httpClientOnce sync.Once
+	httpClients   = make(map[time.Duration]*http.Client)
+	httpClientsMu sync.Mutex
+	// 所有上游请求共用的重试与熔断层
+	upstreamTransport = newRetryTransport(http.DefaultTransport)
 )
 
-// GetHTTPClient 获取复用的 HTTP Client
+// GetHTTPClient 获取复用的 HTTP Client（失败时自动退避重试，主机连续失败时熔断）
+// timeout 为包含全部重试与等待在内的总超时，每次尝试平分剩余时间
 func GetHTTPClient(timeout time.Duration) *http.Client {
-	httpClientOnce.Do(func() {
-		httpClient = &http.Client{
-			Timeout: timeout,
+	httpClientsMu.Lock()
+	defer httpClientsMu.Unlock()
+
+	client, ok := httpClients[timeout]
+	if !ok {
+		client = &http.Client{
+			Timeout:   timeout,
+			Transport: upstreamTransport,
 		}
-	})
-	return httpClient
+		httpClients[timeout] = client
+	}
+	return client
+}
+
+// CircuitOpen 判断主机是否处于熔断状态
+func CircuitOpen(host string) bool {
+	return upstreamTransport.breaker(host).isOpen()
 }
@@ -0,0 +1,312 @@
+package apexapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// 满员人数
+	LFGSquadSize = 3
+	// 组队信息的有效期
+	LFGDefaultTTL = 30 * time.Minute
+	// 已结束的组队记录保留时长
+	lfgRetention = 7 * 24 * time.Hour
+)
+
+var (
+	ErrLFGNotFound      = errors.New("当前没有可加入的组队")
+	ErrLFGAlreadyJoined = errors.New("你已经在这个队伍里了")
+)
+
+// LFGMember 组队成员
+type LFGMember struct {
+	QQ        string
+	EAID      string
+	RankScore int
+	JoinedAt  time.Time
+}
+
+// LFGEntry 一条组队信息
+type LFGEntry struct {
+	ID        int64
+	GroupID   string
+	Mode      string // 模式代码，可为空
+	Rank      string // 段位要求，可为空
+	Creator   string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	Members   []LFGMember
+}
+
+// Full 是否已满员
+func (e LFGEntry) Full() bool {
+	return len(e.Members) >= LFGSquadSize
+}
+
+// LFGData 组队信息存储，与玩家绑定数据共用同一个数据库
+type LFGData struct {
+	players *PlayerData
+}
+
+var LFG = LFGData{players: &Players}
+
+// Create 发布组队信息，同一成员在群内之前发布且未满员的组队会被关闭
+func (l LFGData) Create(groupID, mode, rank string, creator PlayerBindingData, ttl time.Duration) (LFGEntry, error) {
+	p := l.players
+	p.Lock.Lock()
+	defer p.Lock.Unlock()
+
+	if err := p.ensureInit(); err != nil {
+		return LFGEntry{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return LFGEntry{}, fmt.Errorf("开始事务失败: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE lfg_entries SET closed = 1 WHERE group_id = ? AND creator = ? AND closed = 0
+	`, groupID, creator.QQ); err != nil {
+		return LFGEntry{}, fmt.Errorf("关闭旧组队失败: %w", err)
+	}
+	cutoff := now.Add(-lfgRetention).Unix()
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM lfg_members WHERE entry_id IN (SELECT id FROM lfg_entries WHERE expires_at < ?)
+	`, cutoff); err != nil {
+		return LFGEntry{}, fmt.Errorf("清理组队记录失败: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM lfg_entries WHERE expires_at < ?", cutoff); err != nil {
+		return LFGEntry{}, fmt.Errorf("清理组队记录失败: %w", err)
+	}
+
+	entry := LFGEntry{
+		GroupID:   groupID,
+		Mode:      mode,
+		Rank:      rank,
+		Creator:   creator.QQ,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO lfg_entries (group_id, mode, rank_req, creator, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, groupID, mode, rank, creator.QQ, now.Unix(), entry.ExpiresAt.Unix())
+	if err != nil {
+		return LFGEntry{}, fmt.Errorf("保存组队失败: %w", err)
+	}
+	if entry.ID, err = res.LastInsertId(); err != nil {
+		return LFGEntry{}, err
+	}
+
+	member := LFGMember{QQ: creator.QQ, EAID: creator.EAID, RankScore: creator.LastRankScore, JoinedAt: now}
+	if err := insertLFGMember(ctx, tx, entry.ID, member); err != nil {
+		return LFGEntry{}, err
+	}
+	entry.Members = []LFGMember{member}
+
+	return entry, tx.Commit()
+}
+
+// Join 加入群内的组队，entryID 为 0 时加入最新的未满员组队；满员后组队关闭
+func (l LFGData) Join(groupID string, entryID int64, member PlayerBindingData) (LFGEntry, error) {
+	p := l.players
+	p.Lock.Lock()
+	defer p.Lock.Unlock()
+
+	if err := p.ensureInit(); err != nil {
+		return LFGEntry{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return LFGEntry{}, fmt.Errorf("开始事务失败: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT id, group_id, mode, rank_req, creator, created_at, expires_at FROM lfg_entries
+		WHERE group_id = ? AND closed = 0 AND expires_at > ?`
+	args := []any{groupID, time.Now().Unix()}
+	if entryID > 0 {
+		query += " AND id = ?"
+		args = append(args, entryID)
+	}
+	query += " ORDER BY created_at DESC LIMIT 1"
+
+	entry, err := scanLFGEntry(tx.QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		return LFGEntry{}, ErrLFGNotFound
+	}
+	if err != nil {
+		return LFGEntry{}, fmt.Errorf("读取组队失败: %w", err)
+	}
+	if entry.Members, err = queryLFGMembers(ctx, tx, entry.ID); err != nil {
+		return LFGEntry{}, err
+	}
+	for _, m := range entry.Members {
+		if m.QQ == member.QQ {
+			return entry, ErrLFGAlreadyJoined
+		}
+	}
+
+	m := LFGMember{QQ: member.QQ, EAID: member.EAID, RankScore: member.LastRankScore, JoinedAt: time.Now()}
+	if err := insertLFGMember(ctx, tx, entry.ID, m); err != nil {
+		return LFGEntry{}, err
+	}
+	entry.Members = append(entry.Members, m)
+
+	if entry.Full() {
+		if _, err := tx.ExecContext(ctx, "UPDATE lfg_entries SET closed = 1 WHERE id = ?", entry.ID); err != nil {
+			return LFGEntry{}, fmt.Errorf("关闭组队失败: %w", err)
+		}
+	}
+	return entry, tx.Commit()
+}
+
+// Open 获取群内未过期且未满员的组队（最新的在前）
+func (l LFGData) Open(groupID string) ([]LFGEntry, error) {
+	p := l.players
+	p.Lock.RLock()
+	defer p.Lock.RUnlock()
+
+	if err := p.ensureInit(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rows, err := p.db.QueryContext(ctx, `
+		SELECT id, group_id, mode, rank_req, creator, created_at, expires_at FROM lfg_entries
+		WHERE group_id = ? AND closed = 0 AND expires_at > ? ORDER BY created_at DESC
+	`, groupID, time.Now().Unix())
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []LFGEntry
+	for rows.Next() {
+		entry, err := scanLFGEntry(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range entries {
+		if entries[i].Members, err = queryLFGMembers(ctx, p.db, entries[i].ID); err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
+
+// rowScanner 兼容 *sql.Row 与 *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// queryer 兼容数据库连接与事务
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+func scanLFGEntry(row rowScanner) (LFGEntry, error) {
+	var entry LFGEntry
+	var created, expires int64
+	if err := row.Scan(&entry.ID, &entry.GroupID, &entry.Mode, &entry.Rank, &entry.Creator, &created, &expires); err != nil {
+		return LFGEntry{}, err
+	}
+	entry.CreatedAt = time.Unix(created, 0)
+	entry.ExpiresAt = time.Unix(expires, 0)
+	return entry, nil
+}
+
+func queryLFGMembers(ctx context.Context, q queryer, entryID int64) ([]LFGMember, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT qq_id, ea_id, rank_score, joined_at FROM lfg_members
+		WHERE entry_id = ? ORDER BY joined_at, rowid
+	`, entryID)
+	if err != nil {
+		return nil, fmt.Errorf("读取组队成员失败: %w", err)
+	}
+	defer rows.Close()
+
+	var members []LFGMember
+	for rows.Next() {
+		var m LFGMember
+		var joined int64
+		if err := rows.Scan(&m.QQ, &m.EAID, &m.RankScore, &joined); err != nil {
+			return nil, err
+		}
+		m.JoinedAt = time.Unix(joined, 0)
+		members = append(members, m)
+	}
+	return members, rows.Err()
+}
+
+func insertLFGMember(ctx context.Context, tx *instrumentedTx, entryID int64, m LFGMember) error {
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO lfg_members (entry_id, qq_id, ea_id, rank_score, joined_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, entryID, m.QQ, m.EAID, m.RankScore, m.JoinedAt.Unix()); err != nil {
+		return fmt.Errorf("保存组队成员失败: %w", err)
+	}
+	return nil
+}
+
+// formatMemberRank 根据段位分数显示成员段位
+func formatMemberRank(score int) string {
+	if score <= 0 {
+		return "未知段位"
+	}
+	ladder, err := GetRankLadder()
+	if err != nil {
+		return fmt.Sprintf("%d 分", score)
+	}
+	progress, ok := ladder.Progress(score)
+	if !ok {
+		return fmt.Sprintf("%d 分", score)
+	}
+	return fmt.Sprintf("%s·%d 分", progress.Current, score)
+}
+
+// FormatLFGEntry 格式化组队信息，包含每名成员的段位
+func FormatLFGEntry(entry LFGEntry) string {
+	var output strings.Builder
+	title := "组队"
+	if entry.Mode != "" {
+		title = GetModeName(entry.Mode) + title
+	}
+	output.WriteString(fmt.Sprintf("【%s #%d】%d/%d", title, entry.ID, len(entry.Members), LFGSquadSize))
+	if entry.Rank != "" {
+		output.WriteString(fmt.Sprintf("  段位要求：%s", entry.Rank))
+	}
+	output.WriteString("\n")
+	for i, m := range entry.Members {
+		output.WriteString(fmt.Sprintf("  %d. %s（%s）\n", i+1, m.EAID, formatMemberRank(m.RankScore)))
+	}
+	if !entry.Full() {
+		output.WriteString(fmt.Sprintf("有效期至 %s，使用 /a加入 %d 加入", entry.ExpiresAt.Local().Format("15:04"), entry.ID))
+	}
+	return output.String()
+}
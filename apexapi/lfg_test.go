@@ -0,0 +1,89 @@
+package apexapi
+
+import (
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestPlayerData 创建使用临时数据库的 PlayerData
+func newTestPlayerData(t *testing.T) *PlayerData {
+	t.Helper()
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+	if _, err := db.Exec(migrationSQL); err != nil {
+		t.Fatalf("执行数据库迁移失败: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return &PlayerData{db: &instrumentedDB{db}}
+}
+
+func TestLFGJoinUntilFull(t *testing.T) {
+	lfg := LFGData{players: newTestPlayerData(t)}
+	const group = "group1"
+
+	entry, err := lfg.Create(group, "ranked", "钻石", PlayerBindingData{QQ: "a", EAID: "PlayerA", LastRankScore: 11200}, time.Hour)
+	if err != nil {
+		t.Fatalf("发起组队失败: %v", err)
+	}
+
+	if _, err := lfg.Join(group, 0, PlayerBindingData{QQ: "a", EAID: "PlayerA"}); !errors.Is(err, ErrLFGAlreadyJoined) {
+		t.Errorf("重复加入应返回 ErrLFGAlreadyJoined，得到 %v", err)
+	}
+	if _, err := lfg.Join("other", 0, PlayerBindingData{QQ: "b", EAID: "PlayerB"}); !errors.Is(err, ErrLFGNotFound) {
+		t.Errorf("其他群不应看到该组队，得到 %v", err)
+	}
+
+	joined, err := lfg.Join(group, entry.ID, PlayerBindingData{QQ: "b", EAID: "PlayerB", LastRankScore: 9000})
+	if err != nil || joined.Full() {
+		t.Fatalf("第二人加入失败: %v %+v", err, joined)
+	}
+	full, err := lfg.Join(group, 0, PlayerBindingData{QQ: "c", EAID: "PlayerC"})
+	if err != nil || !full.Full() {
+		t.Fatalf("第三人加入后应满员: %v %+v", err, full)
+	}
+	if full.Members[0].EAID != "PlayerA" || full.Members[2].EAID != "PlayerC" {
+		t.Errorf("成员顺序错误: %+v", full.Members)
+	}
+
+	// 满员后不再可加入
+	if _, err := lfg.Join(group, 0, PlayerBindingData{QQ: "d", EAID: "PlayerD"}); !errors.Is(err, ErrLFGNotFound) {
+		t.Errorf("满员的组队不应可加入，得到 %v", err)
+	}
+	if open, err := lfg.Open(group); err != nil || len(open) != 0 {
+		t.Errorf("不应有招募中的组队: %v %+v", err, open)
+	}
+
+	if msg := FormatLFGEntry(full); !strings.Contains(msg, "3/3") || !strings.Contains(msg, "PlayerB") {
+		t.Errorf("组队信息格式错误: %s", msg)
+	}
+}
+
+func TestLFGCreateReplacesOwnEntry(t *testing.T) {
+	lfg := LFGData{players: newTestPlayerData(t)}
+	creator := PlayerBindingData{QQ: "a", EAID: "PlayerA"}
+
+	if _, err := lfg.Create("g", "", "", creator, time.Hour); err != nil {
+		t.Fatalf("发起组队失败: %v", err)
+	}
+	second, err := lfg.Create("g", "", "", creator, time.Hour)
+	if err != nil {
+		t.Fatalf("发起组队失败: %v", err)
+	}
+	open, err := lfg.Open("g")
+	if err != nil || len(open) != 1 || open[0].ID != second.ID {
+		t.Errorf("重新发起后应只保留最新的组队: %v %+v", err, open)
+	}
+
+	if _, err := lfg.Create("g", "", "", PlayerBindingData{QQ: "b", EAID: "PlayerB"}, -time.Minute); err != nil {
+		t.Fatalf("发起组队失败: %v", err)
+	}
+	if open, _ := lfg.Open("g"); len(open) != 1 {
+		t.Errorf("已过期的组队不应出现在列表中: %+v", open)
+	}
+}
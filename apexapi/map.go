@@ -1,6 +1,8 @@
 package apexapi
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -13,6 +15,9 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -109,19 +114,58 @@ func getMapInfo(mapRotate MapRotateInfo) []MapInfo {
 	}
 }
 
-// 所有地图的轮换信息
-type MapRotate struct {
-	Battle_royale MapRotateInfo `json:"battle_royale"`
-	Ranked        MapRotateInfo `json:"ranked"`
-	Ltm           MapRotateInfo `json:"ltm"`
+// MapRotate 所有模式的轮换信息，键为模式代码（如 battle_royale、ranked、arenas）
+type MapRotate map[string]MapRotateInfo
+
+// UnmarshalJSON 逐个模式解析，跳过接口返回的无法识别或没有当前地图的模式
+func (mr *MapRotate) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	result := make(MapRotate, len(raw))
+	for mode, v := range raw {
+		var info MapRotateInfo
+		if err := json.Unmarshal(v, &info); err != nil {
+			continue
+		}
+		if info.Current.Code == "" {
+			continue
+		}
+		result[mode] = info
+	}
+	*mr = result
+	return nil
+}
+
+// 模式的展示顺序，未列出的模式按代码排序追加在后
+var mapModeOrder = []string{"battle_royale", "ranked", "ltm", "arenas", "arenasRanked", "control", "freedm", "tdm"}
+
+// Modes 按展示顺序返回所有模式代码
+func (mr MapRotate) Modes() []string {
+	modes := make([]string, 0, len(mr))
+	for _, mode := range mapModeOrder {
+		if _, ok := mr[mode]; ok {
+			modes = append(modes, mode)
+		}
+	}
+	var others []string
+	for mode := range mr {
+		if !slices.Contains(mapModeOrder, mode) {
+			others = append(others, mode)
+		}
+	}
+	sort.Strings(others)
+	return append(modes, others...)
 }
 
 func getMapRotateData(mr MapRotate) []MapRotateInfo {
-	return []MapRotateInfo{
-		mr.Battle_royale,
-		mr.Ranked,
-		mr.Ltm,
+	modes := mr.Modes()
+	result := make([]MapRotateInfo, 0, len(modes))
+	for _, mode := range modes {
+		result = append(result, mr[mode])
 	}
+	return result
 }
 
 // ============ 缓存与线程安全 ============
@@ -129,22 +173,25 @@ func getMapRotateData(mr MapRotate) []MapRotateInfo {
 var (
 	cachedMapRotate MapRotate
 	cacheExpiresAt  time.Time
+	cachedMapStale  bool      // 缓存中是接口失败时推进的旧数据
+	lastMapFetchAt  time.Time // 最近一次成功获取的时间
+	lastMapFetchErr error     // 最近一次获取的错误，成功时为 nil
 	mapCacheLock    sync.RWMutex
 	mapCacheOnce    sync.Once
 	mapCacheInitErr error
 )
 
-// GetEarliestEndTime 获取所有 Current Map 中最早的 EndTime
+// GetEarliestEndTime 获取所有 Current Map 中尚未结束的最早 EndTime
 func GetEarliestEndTime(mr MapRotate) time.Time {
-	times := []time.Time{
-		time.Time(mr.Battle_royale.Current.EndTime),
-		time.Time(mr.Ranked.Current.EndTime),
-		time.Time(mr.Ltm.Current.EndTime),
-	}
-
-	min := times[0]
-	for _, t := range times[1:] {
-		if t.Before(min) {
+	now := time.Now()
+	var min time.Time
+	for _, info := range mr {
+		t := time.Time(info.Current.EndTime)
+		if !t.After(now) {
+			// 已结束的模式（接口未更新或模式已下线）不参与计算，避免缓存立即失效
+			continue
+		}
+		if min.IsZero() || t.Before(min) {
 			min = t
 		}
 	}
@@ -158,62 +205,73 @@ func RefreshMapCache() {
 	})
 }
 
-// GetMapRotate 获取地图轮换（带缓存）
+// GetMapRotate 获取地图轮换（带缓存），接口失败时回退到上次成功获取的数据
 func GetMapRotate() (MapRotate, error) {
+	mapRotate, _, err := getMapRotate()
+	return mapRotate, err
+}
+
+// getMapRotate 获取地图轮换，stale 表示接口失败、返回的是推进后的旧数据
+func getMapRotate() (mapRotate MapRotate, stale bool, err error) {
 	// 快速路径：检查缓存是否有效
 	mapCacheLock.RLock()
 	if !cacheExpiresAt.IsZero() && time.Now().Before(cacheExpiresAt) {
-		result := cachedMapRotate
+		result, stale := cachedMapRotate, cachedMapStale
 		mapCacheLock.RUnlock()
-		return result, nil
+		recordCache("map", true)
+		return result, stale, nil
 	}
 	mapCacheLock.RUnlock()
+	recordCache("map", false)
 
 	// 缓存无效，重新获取
-	return GetMapRotateFromAPI()
+	mapRotate, err = GetMapRotateFromAPI()
+	if err == nil {
+		return mapRotate, false, nil
+	}
+	if fallback, ok := staleMapRotate(time.Now()); ok {
+		botlog.Warnf("获取地图轮换失败，使用上次保存的数据: %v", err)
+		return fallback, true, nil
+	}
+	return MapRotate{}, false, err
 }
 
-// GetMapRotateFromAPI 从 API 获取地图轮换（不带缓存）
+// GetMapRotateFromAPI 从数据来源获取地图轮换（不带缓存）
 func GetMapRotateFromAPI() (MapRotate, error) {
-	client := GetHTTPClient(15 * time.Second)
-	req, err := http.NewRequest("GET", "https://lil2-gateway.apexlegendsstatus.com/gateway.php?qt=map", nil)
+	mapRotate, err := GetStatsProvider().MapRotation(context.Background())
 	if err != nil {
-		return MapRotate{}, fmt.Errorf("%w: %v", ErrRequestCreateFailed, err)
+		mapCacheLock.Lock()
+		lastMapFetchErr = err
+		mapCacheLock.Unlock()
+		return MapRotate{}, err
 	}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return MapRotate{}, fmt.Errorf("%w: %v", ErrRequestFailed, err)
-	}
-	defer resp.Body.Close()
+	// 更新缓存（写锁）
+	mapCacheLock.Lock()
+	cachedMapRotate = mapRotate
+	cacheExpiresAt = GetEarliestEndTime(mapRotate)
+	cachedMapStale = false
+	lastMapFetchAt = time.Now()
+	lastMapFetchErr = nil
+	mapCacheLock.Unlock()
 
-	limitReader := io.LimitReader(resp.Body, 5<<10)
-	body, err := io.ReadAll(limitReader)
-	if err != nil {
-		return MapRotate{}, ErrReadResponseFailed
+	if err := saveMapSnapshot(mapRotate); err != nil {
+		botlog.Warnf("保存地图轮换数据失败: %v", err)
 	}
 
-	if resp.StatusCode == http.StatusOK {
-		var mapRotate MapRotate
-		var mapRaw struct {
-			MapRotate MapRotate `json:"rotation"`
-		}
-
-		if err := json.Unmarshal(body, &mapRaw); err != nil {
-			return MapRotate{}, ErrInvalidJSON
-		}
-		mapRotate = mapRaw.MapRotate
-
-		// 更新缓存（写锁）
-		mapCacheLock.Lock()
-		cachedMapRotate = mapRotate
-		cacheExpiresAt = GetEarliestEndTime(mapRotate)
-		mapCacheLock.Unlock()
-
-		return mapRotate, nil
+	// 记录轮换数据，用于推算时间表
+	if err := MapHistory.Record(mapRotate); err != nil {
+		botlog.Warnf("记录地图轮换失败: %v", err)
 	}
 
-	return MapRotate{}, ErrStatusCode(resp.StatusCode)
+	return mapRotate, nil
+}
+
+// LastMapFetch 获取最近一次成功获取地图轮换的时间（从未成功时为零值），以及最近一次获取的错误
+func LastMapFetch() (time.Time, error) {
+	mapCacheLock.RLock()
+	defer mapCacheLock.RUnlock()
+	return lastMapFetchAt, lastMapFetchErr
 }
 
 // ForceRefreshMapCache 强制刷新地图缓存
@@ -221,6 +279,7 @@ func ForceRefreshMapCache() (MapRotate, error) {
 	mapCacheLock.Lock()
 	cachedMapRotate = MapRotate{}
 	cacheExpiresAt = time.Time{}
+	cachedMapStale = false
 	mapCacheLock.Unlock()
 
 	mapCacheOnce = sync.Once{} // 重置 Once
@@ -257,25 +316,55 @@ func GetMapName(code string) string {
 	return trans.Translate(code)
 }
 
-// GetModeName 获取模式中文名（线程安全）
-func GetModeName(code string) string {
+// getModeTranslator 获取模式翻译器（线程安全，首次调用时加载）
+func getModeTranslator() *tools.Translator {
 	translatorLock.RLock()
 	trans := modeTranslator
 	translatorLock.RUnlock()
 
 	if trans == nil {
 		t, err := tools.NewTranslator(modeDictPath)
-		if err == nil {
-			translatorLock.Lock()
-			modeTranslator = t
-			translatorLock.Unlock()
-			return t.Translate(code)
+		if err != nil {
+			return nil
 		}
+		translatorLock.Lock()
+		modeTranslator = t
+		translatorLock.Unlock()
+		return t
+	}
+	return trans
+}
+
+// GetModeName 获取模式中文名（线程安全）
+func GetModeName(code string) string {
+	trans := getModeTranslator()
+	if trans == nil {
 		return code
 	}
 	return trans.Translate(code)
 }
 
+// ParseMapMode 将用户输入的模式（模式代码或中文名）解析为模式代码
+func ParseMapMode(name string) (string, bool) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", false
+	}
+	for _, mode := range mapModeOrder {
+		if strings.EqualFold(mode, name) {
+			return mode, true
+		}
+	}
+	trans := getModeTranslator()
+	if trans == nil {
+		return "", false
+	}
+	if _, ok := trans.Lookup(name); ok {
+		return name, true
+	}
+	return trans.ReverseLookup(name)
+}
+
 // ============ 图片缓存 ============
 
 // CacheImage 下载并缓存图片（线程安全）
@@ -305,7 +394,7 @@ func CacheImage(urlLink string) (string, error) {
 
 	// 下载图片
 	client := GetHTTPClient(10 * time.Second)
-	req, err := http.NewRequest("GET", urlLink, nil)
+	req, err := http.NewRequestWithContext(withEndpoint(context.Background(), endpointImage), "GET", urlLink, nil)
 	if err != nil {
 		return "", fmt.Errorf("%w: 创建请求失败: %v", ErrRequestCreateFailed, err)
 	}
@@ -313,12 +402,12 @@ func CacheImage(urlLink string) (string, error) {
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("%w: 下载失败: %v", ErrRequestFailed, err)
+		return "", wrapRequestError(err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return "", ErrStatusCode(resp.StatusCode)
+		return "", StatusError(resp.StatusCode)
 	}
 
 	// 创建并写入文件（带 O_EXCL 防止竞态）
@@ -403,83 +492,98 @@ func ResizeImage(img image.Image, width, height int, options ...bool) image.Imag
 	return resizedImg
 }
 
-// GenerateMapImage 生成地图轮换信息图片
-func GenerateMapImage() (string, error) {
-	mapRotate, err := GetMapRotate()
-	if err != nil {
-		return "", fmt.Errorf("获取地图轮换信息失败: %w", err)
-	}
-
-	// 获取商店倒计时
-	storeCountdown, _ := GetStoreCountdown()
+// 地图轮换图片布局
+const (
+	mapImageWidth   = 960
+	mapPanelHeight  = 300
+	mapHeaderHeight = 130 // 两行倒计时：商店更新、排位结束
+)
 
+// getMapFontPath 获取地图图片使用的字体路径
+func getMapFontPath() (string, error) {
 	assetDir, err := GetAssetPath()
 	if err != nil {
 		return "", fmt.Errorf("获取资源目录失败: %w", err)
 	}
-	fontPath := filepath.Join(assetDir, "Font", "海报粗圆体.ttf")
+	return filepath.Join(assetDir, "Font", "海报粗圆体.ttf"), nil
+}
 
-	selMap := []string{"battle_royale", "ranked", "ltm"}
-	images := make([]image.Image, 0, 3)
+// mapPanel 单个模式的地图面板
+type mapPanel struct {
+	mode    string
+	img     *image.RGBA // 不含剩余时间的面板
+	endTime time.Time   // 当前地图的结束时间
+}
 
-	for i, modeMap := range getMapRotateData(mapRotate) {
-		current := modeMap.Current
-		next := modeMap.Next
+// renderMapPanels 按展示顺序绘制各模式的地图面板（不含随时间变化的剩余时间）
+func renderMapPanels(mapRotate MapRotate, fontPath string) ([]mapPanel, error) {
+	modes := mapRotate.Modes()
+	panels := make([]mapPanel, 0, len(modes))
+
+	for _, mode := range modes {
+		current := mapRotate[mode].Current
+		next := mapRotate[mode].Next
 
 		// 缓存并读取当前地图图片
 		imgPath, err := CacheImage(current.Asset)
 		if err != nil {
-			return "", fmt.Errorf("缓存图片失败: %w", err)
+			return nil, fmt.Errorf("缓存图片失败: %w", err)
 		}
 
 		imgFile, err := os.Open(imgPath)
 		if err != nil {
-			return "", fmt.Errorf("打开图片失败: %w", err)
+			return nil, fmt.Errorf("打开图片失败: %w", err)
 		}
 		origImg, _, err := image.Decode(imgFile)
 		_ = imgFile.Close()
 		if err != nil {
-			return "", fmt.Errorf("解码图片失败: %w", err)
+			return nil, fmt.Errorf("解码图片失败: %w", err)
 		}
 
 		// 缩放到目标尺寸
-		resizedImg := ResizeImage(origImg, 960, 300)
+		resizedImg := ResizeImage(origImg, mapImageWidth, mapPanelHeight)
 
 		// 创建可编辑副本
 		dst := image.NewRGBA(resizedImg.Bounds())
 		draw.Draw(dst, dst.Bounds(), resizedImg, image.Point{}, draw.Src)
 
-		// 构造文本信息
-		endTimeStr := time.Time(current.EndTime).Format("2006-01-02 15:04:05")
-		remaining := time.Until(time.Time(current.EndTime))
-		if remaining < 0 {
-			remaining = 0
-		}
-		remainingStr := FormatDuration(remaining)
-
 		textLines := []tools.TextLine{
-			{Text: GetModeName(selMap[i]), Size: 50, X: 20, Y: 60, FontPath: fontPath},
+			{Text: GetModeName(mode), Size: 50, X: 20, Y: 60, FontPath: fontPath},
 			{Text: GetMapName(current.Code), Size: 80, X: 20, Y: 150, FontPath: fontPath},
-			{Text: fmt.Sprintf("结束时间：%s（%s）", endTimeStr, remainingStr), Size: 30, X: 20, Y: 210, FontPath: fontPath},
-			{Text: fmt.Sprintf("下一轮换：%s", GetMapName(next.Code)), Size: 30, X: 20, Y: 250, FontPath: fontPath},
+		}
+		if next.Code != "" {
+			textLines = append(textLines, tools.TextLine{Text: fmt.Sprintf("下一轮换：%s", GetMapName(next.Code)), Size: 30, X: 20, Y: 250, FontPath: fontPath})
 		}
 
 		// 直接在内存图片上绘制文本
 		tools.AddTextToImageInPlace(dst, textLines)
 
-		images = append(images, dst)
+		panels = append(panels, mapPanel{mode: mode, img: dst, endTime: time.Time(current.EndTime)})
 	}
 
-	// 创建最终图片（添加倒计时栏在顶部）
-	const headerHeight = 80
-	finalImg := image.NewRGBA(image.Rect(0, 0, 960, 900+headerHeight))
+	return panels, nil
+}
+
+// composeMapImage 将面板竖向拼接在倒计时栏下方，并绘制实时倒计时
+func composeMapImage(panels []mapPanel, fontPath string, stale bool) *image.RGBA {
+	finalImg := image.NewRGBA(image.Rect(0, 0, mapImageWidth, mapHeaderHeight+len(panels)*mapPanelHeight))
+	endTimes := make([]time.Time, 0, len(panels))
+	for i, panel := range panels {
+		dstRect := image.Rect(0, mapHeaderHeight+i*mapPanelHeight, mapImageWidth, mapHeaderHeight+(i+1)*mapPanelHeight)
+		draw.Draw(finalImg, dstRect, panel.img, image.Point{}, draw.Src)
+		endTimes = append(endTimes, panel.endTime)
+	}
+	drawMapCountdown(finalImg, endTimes, fontPath, stale)
+	return finalImg
+}
+
+// drawMapCountdown 在底图上绘制商店倒计时栏与各模式的结束时间（随请求时间变化的部分），stale 时标注数据可能已过期
+func drawMapCountdown(dst *image.RGBA, endTimes []time.Time, fontPath string, stale bool) {
+	// 获取商店倒计时
+	storeCountdown, _ := GetStoreCountdown()
 
 	// 绘制倒计时栏背景
-	for y := 0; y < headerHeight; y++ {
-		for x := 0; x < 960; x++ {
-			finalImg.Set(x, y, color.RGBA{30, 30, 40, 255})
-		}
-	}
+	draw.Draw(dst, image.Rect(0, 0, mapImageWidth, mapHeaderHeight), image.NewUniform(color.RGBA{30, 30, 40, 255}), image.Point{}, draw.Src)
 
 	// 添加倒计时文本 - 布局：商店更新倒计时：[时间]                        商店更新：MM-DD HH:mm
 	headerLines := []tools.TextLine{}
@@ -535,51 +639,179 @@ func GenerateMapImage() (string, error) {
 		})
 	}
 
-	tools.AddTextToImageInPlace(finalImg, headerLines)
+	// 第二行：排位阶段结束倒计时
+	status, ok, err := GetSeasonStatus()
+	if err != nil {
+		botlog.Warnf("获取赛季信息失败: %v", err)
+	}
+	if ok && status.Split != nil && status.SplitRemaining() > 0 {
+		headerLines = append(headerLines, tools.TextLine{
+			Text:     "排位结束倒计时：",
+			Size:     32,
+			X:        30,
+			Y:        105,
+			FontPath: fontPath,
+			Color:    color.RGBA{200, 200, 200, 255},
+		})
+		headerLines = append(headerLines, tools.TextLine{
+			Text:     FormatDuration(status.SplitRemaining()),
+			Size:     42,
+			X:        320,
+			Y:        110,
+			FontPath: fontPath,
+			Color:    color.RGBA{120, 200, 255, 255},
+		})
+		headerLines = append(headerLines, tools.TextLine{
+			Text:      fmt.Sprintf("排位结束：%s", status.Split.End.Local().Format("01-02 15:04")),
+			Size:      28,
+			X:         930,
+			Y:         105,
+			FontPath:  fontPath,
+			Color:     color.RGBA{200, 200, 200, 255},
+			Alignment: "right",
+		})
+	} else {
+		headerLines = append(headerLines, tools.TextLine{
+			Text:     "排位结束还有：",
+			Size:     32,
+			X:        30,
+			Y:        105,
+			FontPath: fontPath,
+			Color:    color.RGBA{200, 200, 200, 255},
+		})
+		headerLines = append(headerLines, tools.TextLine{
+			Text:     "暂无信息",
+			Size:     32,
+			X:        260,
+			Y:        105,
+			FontPath: fontPath,
+			Color:    color.RGBA{150, 150, 150, 255},
+		})
+	}
 
-	// 竖向拼接三张模式图片（在倒计时栏下方）
-	for i, img := range images {
-		dstRect := image.Rect(0, headerHeight+i*300, 960, headerHeight+(i+1)*300)
-		draw.CatmullRom.Scale(finalImg, dstRect, img, img.Bounds(), draw.Over, nil)
+	// 各模式的结束时间与剩余时间
+	for i, endTime := range endTimes {
+		remaining := time.Until(endTime)
+		if remaining < 0 {
+			remaining = 0
+		}
+		headerLines = append(headerLines, tools.TextLine{
+			Text:     fmt.Sprintf("结束时间：%s（%s）", endTime.Format("2006-01-02 15:04:05"), FormatDuration(remaining)),
+			Size:     30,
+			X:        20,
+			Y:        mapHeaderHeight + i*mapPanelHeight + 210,
+			FontPath: fontPath,
+		})
+		if stale {
+			headerLines = append(headerLines, tools.TextLine{
+				Text:      "数据可能已过期",
+				Size:      30,
+				X:         mapImageWidth - 20,
+				Y:         mapHeaderHeight + i*mapPanelHeight + 60,
+				FontPath:  fontPath,
+				Color:     color.RGBA{255, 90, 90, 255},
+				Alignment: "right",
+			})
+		}
 	}
 
-	// 保存到临时目录
+	tools.AddTextToImageInPlace(dst, headerLines)
+}
+
+// encodeJPEG 将图片编码为 JPEG 数据
+func encodeJPEG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 95}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeFileAtomic 先写入同目录下的临时文件再重命名，避免读取到写了一半的文件
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
+
+// getMapResultPath 获取地图结果图片的保存路径
+func getMapResultPath() (string, error) {
 	cacheDir, err := GetCachePath()
 	if err != nil {
 		return "", fmt.Errorf("获取缓存目录失败: %w", err)
 	}
-	finalImgPath := filepath.Join(cacheDir, "map_result.jpg")
+	if err := os.MkdirAll(cacheDir, os.ModePerm); err != nil {
+		return "", fmt.Errorf("创建缓存目录失败: %w", err)
+	}
+	return filepath.Join(cacheDir, "map_result.jpg"), nil
+}
+
+// GenerateMapImage 生成地图轮换信息图片（同步生成并保存，返回图片路径）
+func GenerateMapImage() (string, error) {
+	mapRotate, stale, err := getMapRotate()
+	if err != nil {
+		return "", fmt.Errorf("获取地图轮换信息失败: %w", err)
+	}
 
-	finalImgFile, err := os.Create(finalImgPath)
+	fontPath, err := getMapFontPath()
 	if err != nil {
-		return "", fmt.Errorf("创建最终图片文件失败: %w", err)
+		return "", err
 	}
-	defer finalImgFile.Close()
 
-	if err := jpeg.Encode(finalImgFile, finalImg, &jpeg.Options{Quality: 95}); err != nil {
+	panels, err := renderMapPanels(mapRotate, fontPath)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := encodeJPEG(composeMapImage(panels, fontPath, stale))
+	if err != nil {
+		return "", fmt.Errorf("保存最终图片失败: %w", err)
+	}
+
+	finalImgPath, err := getMapResultPath()
+	if err != nil {
+		return "", err
+	}
+	if err := writeFileAtomic(finalImgPath, data); err != nil {
 		return "", fmt.Errorf("保存最终图片失败: %w", err)
 	}
 
 	return finalImgPath, nil
 }
 
-// GetMapResult 获取地图结果（图片路径）
+// GetMapResult 获取地图结果（图片路径），图片由后台渲染器在每次轮换时生成
 func GetMapResult() (string, error) {
-	mapRotate, err := GetMapRotate()
-	if err != nil {
-		botlog.Warnf("获取地图轮换失败: %v", err)
+	if err := mapRenderer.ensureFresh(); err != nil {
+		botlog.Warnf("生成地图图片失败: %v", err)
 		return "", err
 	}
+	return getMapResultPath()
+}
 
-	CacheAllImage(mapRotate)
-
-	mapResultPath, err := GenerateMapImage()
+// GetMapResultImage 获取地图结果图片数据（内存中的面板叠加实时倒计时），modes 为空时包含全部模式
+func GetMapResultImage(modes ...string) ([]byte, error) {
+	data, err := mapRenderer.Image(modes)
 	if err != nil {
 		botlog.Warnf("生成地图图片失败: %v", err)
-		return "", err
+		return nil, err
 	}
-
-	return mapResultPath, nil
+	return data, nil
 }
 
 // ============ 路径初始化 ============
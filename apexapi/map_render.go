@@ -0,0 +1,199 @@
+package apexapi
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	botlog "github.com/tencent-connect/botgo/log"
+)
+
+const (
+	// 轮换结束后稍作等待再刷新，避免接口尚未切换到新数据
+	mapRenderDelay = 5 * time.Second
+	// 渲染失败后的重试间隔
+	mapRenderRetryInterval = 30 * time.Second
+	// 叠加倒计时后的图片复用时长，期间内容不变，上传时可命中富媒体缓存
+	mapComposeInterval = 30 * time.Second
+)
+
+// composedMapImage 叠加倒计时后的图片
+type composedMapImage struct {
+	data []byte
+	at   time.Time
+}
+
+// MapRenderer 地图轮换图片渲染器：每次轮换只渲染一次各模式面板，请求时仅拼接并叠加倒计时
+type MapRenderer struct {
+	mu        sync.RWMutex
+	panels    []mapPanel // 按展示顺序排列的模式面板
+	expiresAt time.Time  // 面板对应轮换的 GetEarliestEndTime
+	stale     bool       // 面板使用的是接口失败时的旧数据
+	fontPath  string
+
+	composed map[string]composedMapImage // 按模式组合缓存的图片
+
+	renderMu sync.Mutex // 保证同一时间只有一个渲染任务
+}
+
+var mapRenderer = &MapRenderer{}
+
+// StartMapRenderer 启动后台渲染协程，在每次轮换结束时预先生成地图图片
+func StartMapRenderer(ctx context.Context) {
+	go mapRenderer.run(ctx)
+}
+
+func (r *MapRenderer) run(ctx context.Context) {
+	for {
+		wait := mapRenderRetryInterval
+		if err := r.ensureFresh(); err != nil {
+			botlog.Warnf("后台渲染地图图片失败: %v", err)
+		} else if until := time.Until(r.expiry()) + mapRenderDelay; until > 0 {
+			wait = until
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+// expiry 获取当前面板的过期时间
+func (r *MapRenderer) expiry() time.Time {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.expiresAt
+}
+
+// fresh 判断面板是否仍对应当前轮换
+func (r *MapRenderer) fresh() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.panels != nil && time.Now().Before(r.expiresAt)
+}
+
+// ensureFresh 面板过期时重新渲染
+func (r *MapRenderer) ensureFresh() error {
+	if r.fresh() {
+		return nil
+	}
+
+	r.renderMu.Lock()
+	defer r.renderMu.Unlock()
+
+	// 等待锁期间可能已由其他协程完成渲染
+	if r.fresh() {
+		return nil
+	}
+	return r.render()
+}
+
+// render 获取轮换数据、渲染各模式面板，并原子地写出包含全部模式的 map_result.jpg
+func (r *MapRenderer) render() error {
+	mapRotate, stale, err := getMapRotate()
+	if err != nil {
+		return fmt.Errorf("获取地图轮换信息失败: %w", err)
+	}
+
+	// 预先缓存下一轮换的地图图片
+	CacheAllImage(mapRotate)
+
+	fontPath, err := getMapFontPath()
+	if err != nil {
+		return err
+	}
+
+	panels, err := renderMapPanels(mapRotate, fontPath)
+	if err != nil {
+		return err
+	}
+
+	expiresAt := GetEarliestEndTime(mapRotate)
+	if stale {
+		// 旧数据只短暂使用，到期后重新请求接口
+		if retryAt := time.Now().Add(mapStaleRetryInterval); expiresAt.IsZero() || retryAt.Before(expiresAt) {
+			expiresAt = retryAt
+		}
+	}
+
+	r.mu.Lock()
+	r.panels = panels
+	r.expiresAt = expiresAt
+	r.stale = stale
+	r.fontPath = fontPath
+	r.composed = make(map[string]composedMapImage)
+	r.mu.Unlock()
+
+	// 同时保存一份到磁盘，供按路径读取的调用方使用
+	data, err := r.compose(nil)
+	if err != nil {
+		return err
+	}
+	resultPath, err := getMapResultPath()
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(resultPath, data); err != nil {
+		return fmt.Errorf("保存最终图片失败: %w", err)
+	}
+	return nil
+}
+
+// compose 拼接所选模式的面板并叠加实时倒计时，编码为 JPEG；modes 为空时包含全部模式
+func (r *MapRenderer) compose(modes []string) ([]byte, error) {
+	r.mu.RLock()
+	if r.panels == nil {
+		r.mu.RUnlock()
+		return nil, fmt.Errorf("地图图片尚未生成")
+	}
+	panels := make([]mapPanel, 0, len(r.panels))
+	for _, panel := range r.panels {
+		if len(modes) == 0 || slices.Contains(modes, panel.mode) {
+			panels = append(panels, panel)
+		}
+	}
+	fontPath, stale := r.fontPath, r.stale
+	r.mu.RUnlock()
+
+	if len(panels) == 0 {
+		return nil, fmt.Errorf("当前没有所选模式的轮换数据")
+	}
+
+	data, err := encodeJPEG(composeMapImage(panels, fontPath, stale))
+	if err != nil {
+		return nil, fmt.Errorf("编码图片失败: %w", err)
+	}
+	return data, nil
+}
+
+// Image 获取带实时倒计时的地图图片数据，modes 为空时包含全部模式
+func (r *MapRenderer) Image(modes []string) ([]byte, error) {
+	if err := r.ensureFresh(); err != nil {
+		return nil, err
+	}
+
+	key := strings.Join(modes, ",")
+	r.mu.RLock()
+	if c, ok := r.composed[key]; ok && time.Since(c.at) < mapComposeInterval {
+		r.mu.RUnlock()
+		return c.data, nil
+	}
+	r.mu.RUnlock()
+
+	data, err := r.compose(modes)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	if r.composed != nil {
+		r.composed[key] = composedMapImage{data: data, at: time.Now()}
+	}
+	r.mu.Unlock()
+	return data, nil
+}
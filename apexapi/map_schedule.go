@@ -0,0 +1,257 @@
+package apexapi
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+const (
+	// 轮换记录的保留时长
+	mapHistoryRetention = 30 * 24 * time.Hour
+	// 相邻轮换视为连续的最大时间误差
+	mapSlotTolerance = time.Minute
+	// 最远预测范围
+	mapPredictHorizon = 7 * 24 * time.Hour
+	// 超过该距离的预测降低一级可信度
+	mapPredictFarAhead = 24 * time.Hour
+)
+
+// Confidence 时间表中轮换时段的可信度
+type Confidence int
+
+const (
+	ConfidenceObserved Confidence = iota // 接口返回的真实数据
+	ConfidenceHigh                       // 已观测到至少两个完整周期
+	ConfidenceMedium                     // 已观测到至少两个完整周期，但距观测数据较远
+	ConfidenceLow                        // 周期重复的证据不足
+)
+
+// String 可信度的中文描述
+func (c Confidence) String() string {
+	switch c {
+	case ConfidenceObserved:
+		return "确定"
+	case ConfidenceHigh:
+		return "预测·高"
+	case ConfidenceMedium:
+		return "预测·中"
+	default:
+		return "预测·低"
+	}
+}
+
+// ScheduledMap 时间表中的一个轮换时段
+type ScheduledMap struct {
+	Mode       string
+	Code       string
+	Start      time.Time
+	End        time.Time
+	Confidence Confidence
+}
+
+// Duration 时段长度
+func (s ScheduledMap) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// slotKey 用于比较两个时段是否为周期中的同一位置（地图与时长均相同）
+func (s ScheduledMap) slotKey() string {
+	return fmt.Sprintf("%s|%d", s.Code, s.Duration().Round(time.Minute)/time.Minute)
+}
+
+// MapHistoryData 地图轮换观测记录，与玩家绑定数据共用同一个数据库
+type MapHistoryData struct {
+	players *PlayerData
+}
+
+var MapHistory = MapHistoryData{players: &Players}
+
+// Record 记录接口返回的当前与下一轮换
+func (h MapHistoryData) Record(mr MapRotate) error {
+	p := h.players
+	p.Lock.Lock()
+	defer p.Lock.Unlock()
+
+	if err := p.ensureInit(); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("开始事务失败: %w", err)
+	}
+	defer tx.Rollback()
+
+	for mode, info := range mr {
+		for _, m := range getMapInfo(info) {
+			start, end := time.Time(m.StartTime), time.Time(m.EndTime)
+			if m.Code == "" || start.IsZero() || !end.After(start) {
+				continue
+			}
+			if _, err := tx.ExecContext(ctx, `
+				INSERT OR REPLACE INTO map_rotation_slots (mode, code, start_time, end_time)
+				VALUES (?, ?, ?, ?)
+			`, mode, m.Code, start.Unix(), end.Unix()); err != nil {
+				return fmt.Errorf("保存轮换记录失败: %w", err)
+			}
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM map_rotation_slots WHERE end_time < ?",
+		time.Now().Add(-mapHistoryRetention).Unix()); err != nil {
+		return fmt.Errorf("清理轮换记录失败: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// Slots 获取某模式的全部观测记录（按开始时间排序）
+func (h MapHistoryData) Slots(mode string) ([]ScheduledMap, error) {
+	p := h.players
+	p.Lock.RLock()
+	defer p.Lock.RUnlock()
+
+	if err := p.ensureInit(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rows, err := p.db.QueryContext(ctx, `
+		SELECT code, start_time, end_time FROM map_rotation_slots
+		WHERE mode = ? ORDER BY start_time
+	`, mode)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var slots []ScheduledMap
+	for rows.Next() {
+		var code string
+		var start, end int64
+		if err := rows.Scan(&code, &start, &end); err != nil {
+			return nil, err
+		}
+		slots = append(slots, ScheduledMap{
+			Mode:       mode,
+			Code:       code,
+			Start:      time.Unix(start, 0),
+			End:        time.Unix(end, 0),
+			Confidence: ConfidenceObserved,
+		})
+	}
+	return slots, rows.Err()
+}
+
+// latestRun 返回最近一段首尾相连的观测记录（机器人离线会造成记录断档）
+func latestRun(slots []ScheduledMap) []ScheduledMap {
+	if len(slots) == 0 {
+		return nil
+	}
+	start := len(slots) - 1
+	for start > 0 {
+		gap := slots[start].Start.Sub(slots[start-1].End)
+		if gap > mapSlotTolerance || gap < -mapSlotTolerance {
+			break
+		}
+		start--
+	}
+	return slots[start:]
+}
+
+// findCycle 寻找最短的重复周期，返回周期长度（时段数）与可信度；找不到时返回 0
+func findCycle(run []ScheduledMap) (int, Confidence) {
+	n := len(run)
+	for period := 1; period < n; period++ {
+		matched := true
+		for i := 0; i+period < n; i++ {
+			if run[i].slotKey() != run[i+period].slotKey() {
+				matched = false
+				break
+			}
+		}
+		if !matched {
+			continue
+		}
+		// 至少观测到两个完整周期才认为周期可信
+		if n >= 2*period {
+			return period, ConfidenceHigh
+		}
+		return period, ConfidenceLow
+	}
+	return 0, ConfidenceLow
+}
+
+// predictSchedule 根据观测记录推算 [from, to) 区间内的轮换时间表
+func predictSchedule(observed []ScheduledMap, from, to time.Time) []ScheduledMap {
+	sort.Slice(observed, func(i, j int) bool { return observed[i].Start.Before(observed[j].Start) })
+
+	var result []ScheduledMap
+	for _, slot := range observed {
+		if slot.End.After(from) && slot.Start.Before(to) {
+			result = append(result, slot)
+		}
+	}
+
+	run := latestRun(observed)
+	if len(run) == 0 || !run[len(run)-1].End.Before(to) {
+		return result
+	}
+	period, confidence := findCycle(run)
+	if period == 0 {
+		return result
+	}
+
+	// 按周期向后推算
+	limit := time.Now().Add(mapPredictHorizon)
+	last := run[len(run)-1]
+	cycle := run[len(run)-period:]
+	for i := 0; last.End.Before(to) && last.End.Before(limit); i++ {
+		tmpl := cycle[i%period]
+		next := ScheduledMap{
+			Mode:       last.Mode,
+			Code:       tmpl.Code,
+			Start:      last.End,
+			End:        last.End.Add(tmpl.Duration()),
+			Confidence: confidence,
+		}
+		if next.Start.Sub(run[len(run)-1].End) >= mapPredictFarAhead && next.Confidence < ConfidenceLow {
+			next.Confidence++
+		}
+		if next.End.After(from) {
+			result = append(result, next)
+		}
+		last = next
+	}
+	return result
+}
+
+// PredictMapSchedule 获取某模式在 [from, to) 区间内的轮换时间表（包含观测与预测的时段）
+func PredictMapSchedule(mode string, from, to time.Time) ([]ScheduledMap, error) {
+	slots, err := MapHistory.Slots(mode)
+	if err != nil {
+		return nil, fmt.Errorf("读取轮换记录失败: %w", err)
+	}
+	return predictSchedule(slots, from, to), nil
+}
+
+// PredictMapAt 获取某模式在指定时间的轮换，无法推算时 ok 为 false
+func PredictMapAt(mode string, at time.Time) (ScheduledMap, bool, error) {
+	schedule, err := PredictMapSchedule(mode, at, at.Add(time.Second))
+	if err != nil {
+		return ScheduledMap{}, false, err
+	}
+	for _, slot := range schedule {
+		if !slot.Start.After(at) && slot.End.After(at) {
+			return slot, true, nil
+		}
+	}
+	return ScheduledMap{}, false, nil
+}
@@ -0,0 +1,84 @@
+package apexapi
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPredictSchedule(t *testing.T) {
+	// 构造两个完整周期：A(90分钟) -> B(120分钟) -> C(90分钟)
+	base := time.Now().Add(-10 * time.Hour).Truncate(time.Minute)
+	cycle := []struct {
+		code string
+		dur  time.Duration
+	}{
+		{"olympus_rotation", 90 * time.Minute},
+		{"worlds_edge_rotation", 120 * time.Minute},
+		{"storm_point_rotation", 90 * time.Minute},
+	}
+	var observed []ScheduledMap
+	start := base
+	for i := 0; i < 6; i++ {
+		c := cycle[i%len(cycle)]
+		observed = append(observed, ScheduledMap{
+			Mode:       "battle_royale",
+			Code:       c.code,
+			Start:      start,
+			End:        start.Add(c.dur),
+			Confidence: ConfidenceObserved,
+		})
+		start = start.Add(c.dur)
+	}
+
+	period, confidence := findCycle(latestRun(observed))
+	if period != 3 || confidence != ConfidenceHigh {
+		t.Fatalf("周期识别错误，得到: %d/%v，期望: 3/%v", period, confidence, ConfidenceHigh)
+	}
+
+	// 观测数据结束后的第一个时段应为周期中的第一张地图
+	lastEnd := observed[len(observed)-1].End
+	schedule := predictSchedule(observed, lastEnd, lastEnd.Add(4*time.Hour))
+	if len(schedule) < 2 {
+		t.Fatalf("预测结果过少: %d", len(schedule))
+	}
+	if schedule[0].Code != "olympus_rotation" || !schedule[0].Start.Equal(lastEnd) {
+		t.Fatalf("预测的第一个时段错误: %+v", schedule[0])
+	}
+	if schedule[1].Code != "worlds_edge_rotation" || schedule[1].Duration() != 120*time.Minute {
+		t.Fatalf("预测的第二个时段错误: %+v", schedule[1])
+	}
+	if schedule[0].Confidence != ConfidenceHigh {
+		t.Fatalf("预测可信度错误: %v", schedule[0].Confidence)
+	}
+}
+
+func TestFindCycleNeedsTwoPeriods(t *testing.T) {
+	now := time.Now().Truncate(time.Minute)
+	var run []ScheduledMap
+	for i, code := range []string{"a", "b", "a", "b"} {
+		start := now.Add(time.Duration(i) * time.Hour)
+		run = append(run, ScheduledMap{Code: code, Start: start, End: start.Add(time.Hour)})
+	}
+	// 只有一个半周期时可信度为低
+	if period, confidence := findCycle(run[:3]); period != 2 || confidence != ConfidenceLow {
+		t.Errorf("3 个时段: %d/%v，期望: 2/%v", period, confidence, ConfidenceLow)
+	}
+	if period, confidence := findCycle(run); period != 2 || confidence != ConfidenceHigh {
+		t.Errorf("4 个时段: %d/%v，期望: 2/%v", period, confidence, ConfidenceHigh)
+	}
+}
+
+func TestPredictScheduleWithGap(t *testing.T) {
+	// 断档之前的记录不参与周期识别
+	now := time.Now().Truncate(time.Minute)
+	observed := []ScheduledMap{
+		{Code: "a", Start: now.Add(-10 * time.Hour), End: now.Add(-9 * time.Hour)},
+		{Code: "b", Start: now.Add(-2 * time.Hour), End: now.Add(-1 * time.Hour)},
+	}
+	if run := latestRun(observed); len(run) != 1 || run[0].Code != "b" {
+		t.Fatalf("最近连续记录错误: %+v", run)
+	}
+	if period, _ := findCycle(latestRun(observed)); period != 0 {
+		t.Fatalf("数据不足时不应识别出周期，得到: %d", period)
+	}
+}
@@ -0,0 +1,126 @@
+package apexapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// 使用旧数据时的缓存时长，到期后重新尝试请求接口
+const mapStaleRetryInterval = time.Minute
+
+// getMapSnapshotPath 获取上次成功获取的地图轮换数据的保存路径
+func getMapSnapshotPath() (string, error) {
+	cacheDir, err := GetCachePath()
+	if err != nil {
+		return "", fmt.Errorf("获取缓存目录失败: %w", err)
+	}
+	if err := os.MkdirAll(cacheDir, os.ModePerm); err != nil {
+		return "", fmt.Errorf("创建缓存目录失败: %w", err)
+	}
+	return filepath.Join(cacheDir, "map_rotation.json"), nil
+}
+
+// saveMapSnapshot 将成功获取的地图轮换保存到磁盘，接口不可用时（包括重启后）作为后备数据
+func saveMapSnapshot(mr MapRotate) error {
+	data, err := json.Marshal(mr)
+	if err != nil {
+		return err
+	}
+	path, err := getMapSnapshotPath()
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data)
+}
+
+// loadMapSnapshot 读取磁盘上保存的地图轮换
+func loadMapSnapshot() (MapRotate, error) {
+	path, err := getMapSnapshotPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var mr MapRotate
+	if err := json.Unmarshal(data, &mr); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidJSON, err)
+	}
+	return mr, nil
+}
+
+// rollMapRotate 根据当前时间推进旧的轮换数据：当前地图已结束时以下一张地图作为当前地图，
+// 下一张也已结束时尝试用 predict 按观测记录推算（都需要能找到该地图的图片），否则保留原数据
+func rollMapRotate(mr MapRotate, now time.Time, predict func(mode string, at time.Time) (ScheduledMap, bool, error)) MapRotate {
+	// 旧数据中出现过的地图图片，推算出的地图没有图片地址
+	assets := make(map[string]string)
+	for _, info := range mr {
+		for _, m := range getMapInfo(info) {
+			if m.Code != "" && m.Asset != "" {
+				assets[m.Code] = m.Asset
+			}
+		}
+	}
+
+	result := make(MapRotate, len(mr))
+	for mode, info := range mr {
+		if time.Time(info.Current.EndTime).After(now) {
+			result[mode] = info
+			continue
+		}
+		if next := info.Next; next.Code != "" && time.Time(next.EndTime).After(now) {
+			// 部分数据来源的下一张地图没有图片地址，渲染时需要图片
+			if next.Asset == "" {
+				next.Asset = assets[next.Code]
+			}
+			if next.Asset != "" {
+				result[mode] = MapRotateInfo{Current: next}
+				continue
+			}
+		}
+		if slot, ok, err := predict(mode, now); err == nil && ok && assets[slot.Code] != "" {
+			result[mode] = MapRotateInfo{Current: MapInfo{
+				Code:      slot.Code,
+				StartTime: UnixTime(slot.Start),
+				EndTime:   UnixTime(slot.End),
+				Asset:     assets[slot.Code],
+			}}
+			continue
+		}
+		result[mode] = info
+	}
+	return result
+}
+
+// staleMapRotate 接口失败时获取后备的地图轮换：优先使用内存中的数据，其次读取磁盘，
+// 推进到当前时间后写入缓存并标记为可能已过期，一段时间后再重新请求接口
+func staleMapRotate(now time.Time) (MapRotate, bool) {
+	mapCacheLock.RLock()
+	base := cachedMapRotate
+	mapCacheLock.RUnlock()
+
+	if len(base) == 0 {
+		snapshot, err := loadMapSnapshot()
+		if err != nil || len(snapshot) == 0 {
+			return nil, false
+		}
+		base = snapshot
+	}
+
+	rolled := rollMapRotate(base, now, PredictMapAt)
+	expiresAt := now.Add(mapStaleRetryInterval)
+	if end := GetEarliestEndTime(rolled); !end.IsZero() && end.Before(expiresAt) {
+		expiresAt = end
+	}
+
+	mapCacheLock.Lock()
+	cachedMapRotate = rolled
+	cacheExpiresAt = expiresAt
+	cachedMapStale = true
+	mapCacheLock.Unlock()
+	return rolled, true
+}
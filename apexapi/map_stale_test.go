@@ -0,0 +1,84 @@
+package apexapi
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestRollMapRotate(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) UnixTime { return UnixTime(now.Add(d)) }
+
+	mr := MapRotate{
+		// 当前地图未结束，保持不变
+		"battle_royale": {
+			Current: MapInfo{Code: "kings_canyon_rotation", StartTime: at(-time.Hour), EndTime: at(time.Hour), Asset: "kc.jpg"},
+			Next:    MapInfo{Code: "broken_moon_rotation", StartTime: at(time.Hour), EndTime: at(2 * time.Hour), Asset: "bm.jpg"},
+		},
+		// 当前地图已结束，以下一张作为当前地图
+		"ranked": {
+			Current: MapInfo{Code: "olympus_rotation", StartTime: at(-2 * time.Hour), EndTime: at(-time.Hour), Asset: "ol.jpg"},
+			Next:    MapInfo{Code: "storm_point_rotation", StartTime: at(-time.Hour), EndTime: at(time.Hour), Asset: "sp.jpg"},
+		},
+		// 两张都已结束，按推算结果使用旧数据中出现过的图片
+		"ltm": {
+			Current: MapInfo{Code: "mp_rr_arena_phase_runner", StartTime: at(-3 * time.Hour), EndTime: at(-2 * time.Hour), Asset: "pr.jpg"},
+			Next:    MapInfo{Code: "mp_rr_arena_skygarden", StartTime: at(-2 * time.Hour), EndTime: at(-time.Hour), Asset: "sg.jpg"},
+		},
+		// 下一张没有图片地址时，使用旧数据中出现过的图片
+		"control": {
+			Current: MapInfo{Code: "mp_rr_arena_habitat", StartTime: at(-2 * time.Hour), EndTime: at(-time.Hour), Asset: "hb.jpg"},
+			Next:    MapInfo{Code: "kings_canyon_rotation", StartTime: at(-time.Hour), EndTime: at(time.Hour)},
+		},
+		// 两张都已结束且无法推算，保留原数据
+		"arenas": {
+			Current: MapInfo{Code: "a", EndTime: at(-2 * time.Hour), Asset: "a.jpg"},
+			Next:    MapInfo{Code: "b", EndTime: at(-time.Hour), Asset: "b.jpg"},
+		},
+	}
+
+	predict := func(mode string, t time.Time) (ScheduledMap, bool, error) {
+		if mode != "ltm" {
+			return ScheduledMap{}, false, nil
+		}
+		return ScheduledMap{Mode: mode, Code: "mp_rr_arena_phase_runner", Start: now.Add(-time.Hour), End: now.Add(time.Hour)}, true, nil
+	}
+
+	rolled := rollMapRotate(mr, now, predict)
+	if rolled["battle_royale"] != mr["battle_royale"] {
+		t.Errorf("battle_royale 不应变化: %+v", rolled["battle_royale"])
+	}
+	if got := rolled["ranked"]; got.Current.Code != "storm_point_rotation" || got.Next.Code != "" {
+		t.Errorf("ranked = %+v", got)
+	}
+	if got := rolled["control"]; got.Current.Code != "kings_canyon_rotation" || got.Current.Asset != "kc.jpg" {
+		t.Errorf("control = %+v", got)
+	}
+	if got := rolled["ltm"].Current; got.Code != "mp_rr_arena_phase_runner" || got.Asset != "pr.jpg" || !time.Time(got.EndTime).Equal(now.Add(time.Hour)) {
+		t.Errorf("ltm = %+v", got)
+	}
+	if rolled["arenas"] != mr["arenas"] {
+		t.Errorf("arenas 不应变化: %+v", rolled["arenas"])
+	}
+	if _, ok := mr["ranked"]; !ok || mr["ranked"].Current.Code != "olympus_rotation" {
+		t.Error("不应修改原数据")
+	}
+}
+
+func TestMapSnapshotRoundTrip(t *testing.T) {
+	end := time.Now().Add(time.Hour).Truncate(time.Second)
+	mr := MapRotate{"ranked": {Current: MapInfo{Name: "Olympus", Code: "olympus_rotation", EndTime: UnixTime(end), Asset: "ol.jpg"}}}
+
+	data, err := json.Marshal(mr)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var got MapRotate
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if c := got["ranked"].Current; c.Code != "olympus_rotation" || c.Asset != "ol.jpg" || !time.Time(c.EndTime).Equal(end) {
+		t.Errorf("round trip = %+v", got)
+	}
+}
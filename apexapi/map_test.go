@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 	"time"
 
@@ -84,9 +85,9 @@ func TestGenerateMapImage(t *testing.T) {
 		t.Fatalf("图片解码失败: %v", err)
 	}
 	b := img.Bounds()
-	// 添加赛季倒计时栏后，高度增加80px（900 + 80 = 980）
-	if b.Dx() != 960 || b.Dy() != 980 {
-		t.Fatalf("图片尺寸不符合预期，得到: %dx%d，期望: 960x980", b.Dx(), b.Dy())
+	// 顶部倒计时栏 130px（商店更新、排位结束两行），每个模式面板 300px
+	if b.Dx() != 960 || b.Dy() <= 130 || (b.Dy()-130)%300 != 0 {
+		t.Fatalf("图片尺寸不符合预期，得到: %dx%d，期望: 960x(130+300*模式数)", b.Dx(), b.Dy())
 	}
 }
 
@@ -129,9 +130,30 @@ func TestGetMapResult(t *testing.T) {
 		t.Fatalf("图片解码失败: %v", err)
 	}
 	b := img.Bounds()
-	// 添加赛季倒计时栏后，高度增加80px（900 + 80 = 980）
-	if b.Dx() != 960 || b.Dy() != 980 {
-		t.Fatalf("图片尺寸不符合预期，得到: %dx%d，期望: 960x980", b.Dx(), b.Dy())
+	// 顶部倒计时栏 130px（商店更新、排位结束两行），每个模式面板 300px
+	if b.Dx() != 960 || b.Dy() <= 130 || (b.Dy()-130)%300 != 0 {
+		t.Fatalf("图片尺寸不符合预期，得到: %dx%d，期望: 960x(130+300*模式数)", b.Dx(), b.Dy())
+	}
+}
+
+func TestMapRotateUnmarshal(t *testing.T) {
+	data := []byte(`{
+		"ltm": {"current": {"map": "Habitat", "code": "freedm_tdm_habitat", "start": 1700000000, "end": 1700003600}},
+		"battle_royale": {"current": {"map": "Olympus", "code": "olympus_rotation", "start": 1700000000, "end": 1700005400}},
+		"zzz_new_mode": {"current": {"map": "Unknown", "code": "unknown_rotation", "start": 1700000000, "end": 1700003600}},
+		"arenas": {"current": {}},
+		"broken": "not an object"
+	}`)
+
+	var mr apexapi.MapRotate
+	if err := json.Unmarshal(data, &mr); err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+
+	// 没有当前地图或无法解析的模式应被跳过，未知模式排在已知模式之后
+	want := []string{"battle_royale", "ltm", "zzz_new_mode"}
+	if got := mr.Modes(); !slices.Equal(got, want) {
+		t.Fatalf("模式顺序不符合预期，得到: %v，期望: %v", got, want)
 	}
 }
 
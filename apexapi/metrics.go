@@ -0,0 +1,84 @@
+package apexapi
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	upstreamRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "apexbot_upstream_requests_total",
+		Help: "上游请求次数（每次重试单独计数）",
+	}, []string{"endpoint", "status"})
+	upstreamDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "apexbot_upstream_request_duration_seconds",
+		Help: "上游请求耗时",
+	}, []string{"endpoint"})
+	cacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "apexbot_cache_requests_total",
+		Help: "缓存查询次数，result 为 hit 或 miss",
+	}, []string{"cache", "result"})
+	sqliteDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "apexbot_sqlite_query_duration_seconds",
+		Help:    "SQLite 语句耗时",
+		Buckets: []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1},
+	}, []string{"op"})
+)
+
+// 上游端点名称，用于指标标签
+const (
+	endpointPlayer   = "player"
+	endpointMap      = "map"
+	endpointCrafting = "crafting"
+	endpointPredator = "predator"
+	endpointStore    = "store"
+	endpointImage    = "image"
+	endpointSeason   = "season"
+	endpointNews     = "news"
+	endpointStatus   = "status"
+	endpointOther    = "other"
+)
+
+type endpointKey struct{}
+
+// withEndpoint 标记请求所属的上游端点
+func withEndpoint(ctx context.Context, endpoint string) context.Context {
+	return context.WithValue(ctx, endpointKey{}, endpoint)
+}
+
+func endpointFrom(ctx context.Context) string {
+	if endpoint, ok := ctx.Value(endpointKey{}).(string); ok {
+		return endpoint
+	}
+	return endpointOther
+}
+
+// observeUpstream 记录一次上游请求的耗时与结果
+func observeUpstream(ctx context.Context, start time.Time, resp *http.Response, err error) {
+	endpoint := endpointFrom(ctx)
+	upstreamDuration.WithLabelValues(endpoint).Observe(time.Since(start).Seconds())
+	status := "error"
+	if err == nil {
+		status = strconv.Itoa(resp.StatusCode)
+	}
+	upstreamRequests.WithLabelValues(endpoint, status).Inc()
+}
+
+// recordCache 记录一次缓存查询结果
+func recordCache(cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	cacheRequests.WithLabelValues(cache, result).Inc()
+}
+
+// observeSQLite 记录一条 SQLite 语句从 start 开始的耗时，配合 defer 使用
+func observeSQLite(op string, start time.Time) {
+	sqliteDuration.WithLabelValues(op).Observe(time.Since(start).Seconds())
+}
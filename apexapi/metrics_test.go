@@ -0,0 +1,72 @@
+package apexapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// histogramCount 返回直方图指定标签的观测次数
+func histogramCount(t *testing.T, h *prometheus.HistogramVec, labelValues ...string) uint64 {
+	t.Helper()
+	var m dto.Metric
+	if err := h.WithLabelValues(labelValues...).(prometheus.Metric).Write(&m); err != nil {
+		t.Fatalf("读取直方图失败: %v", err)
+	}
+	return m.GetHistogram().GetSampleCount()
+}
+
+func TestUpstreamMetrics(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	before503 := testutil.ToFloat64(upstreamRequests.WithLabelValues(endpointPredator, "503"))
+	before200 := testutil.ToFloat64(upstreamRequests.WithLabelValues(endpointPredator, "200"))
+	beforeCount := histogramCount(t, upstreamDuration, endpointPredator)
+
+	var delays []time.Duration
+	client := &http.Client{Transport: newTestTransport(&delays)}
+	req, _ := http.NewRequestWithContext(withEndpoint(context.Background(), endpointPredator), "GET", srv.URL, nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	resp.Body.Close()
+
+	// 每次重试单独计数
+	if got := testutil.ToFloat64(upstreamRequests.WithLabelValues(endpointPredator, "503")) - before503; got != 1 {
+		t.Errorf("503 次数 = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(upstreamRequests.WithLabelValues(endpointPredator, "200")) - before200; got != 1 {
+		t.Errorf("200 次数 = %v, want 1", got)
+	}
+	if got := histogramCount(t, upstreamDuration, endpointPredator) - beforeCount; got != 2 {
+		t.Errorf("耗时观测次数 = %v, want 2", got)
+	}
+}
+
+func TestSQLiteMetrics(t *testing.T) {
+	p := newTestPlayerData(t)
+	before := histogramCount(t, sqliteDuration, "query_row")
+
+	if _, ok := p.Get("nobody"); ok {
+		t.Fatal("不应找到绑定")
+	}
+	if got := histogramCount(t, sqliteDuration, "query_row") - before; got != 1 {
+		t.Errorf("query_row 观测次数 = %v, want 1", got)
+	}
+}
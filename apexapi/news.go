@@ -0,0 +1,359 @@
+package apexapi
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	botlog "github.com/tencent-connect/botgo/log"
+)
+
+const (
+	// 检查新闻的间隔
+	newsPollInterval = 30 * time.Minute
+	// 每次最多推送的新闻条数，避免刷屏
+	newsMaxPushItems = 3
+	// 摘要的最大长度（字符数）
+	newsSummaryLength = 120
+	// 已推送记录的保留时长
+	newsSeenRetention = 90 * 24 * time.Hour
+)
+
+var (
+	htmlTagPattern = regexp.MustCompile(`<[^>]*>`)
+	htmlImgPattern = regexp.MustCompile(`<img[^>]+src=["']([^"']+)["']`)
+	spacePattern   = regexp.MustCompile(`\s+`)
+)
+
+// NewsItem 一条新闻
+type NewsItem struct {
+	ID        string // 去重使用的唯一标识（guid、id 或链接）
+	Title     string
+	Summary   string
+	Link      string
+	Image     string
+	Published time.Time
+}
+
+// FetchNews 获取最新新闻：配置了 news_url 时解析 RSS/Atom，否则使用 apexlegendsstatus 的新闻接口
+func FetchNews(ctx context.Context) ([]NewsItem, error) {
+	url := GetAPIConfig().NewsURL
+	if url == "" {
+		url = "https://lil2-gateway.apexlegendsstatus.com/gateway.php?qt=news"
+	}
+
+	req, err := http.NewRequestWithContext(withEndpoint(ctx, endpointNews), "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRequestCreateFailed, err)
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0")
+
+	resp, err := GetHTTPClient(15 * time.Second).Do(req)
+	if err != nil {
+		return nil, wrapRequestError(err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 2<<20))
+	if err != nil {
+		return nil, ErrReadResponseFailed
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, StatusError(resp.StatusCode)
+	}
+	return parseNews(body)
+}
+
+// parseNews 根据内容自动识别 JSON、RSS 与 Atom 格式
+func parseNews(body []byte) ([]NewsItem, error) {
+	trimmed := strings.TrimSpace(string(body))
+	if strings.HasPrefix(trimmed, "[") || strings.HasPrefix(trimmed, "{") {
+		return parseGatewayNews(body)
+	}
+	return parseFeed(body)
+}
+
+// parseGatewayNews 解析 apexlegendsstatus 的新闻接口
+func parseGatewayNews(body []byte) ([]NewsItem, error) {
+	var raw []struct {
+		Title     string `json:"title"`
+		Link      string `json:"link"`
+		Img       string `json:"img"`
+		ShortDesc string `json:"short_desc"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidJSON, err)
+	}
+
+	items := make([]NewsItem, 0, len(raw))
+	for _, r := range raw {
+		if r.Link == "" && r.Title == "" {
+			continue
+		}
+		items = append(items, NewsItem{
+			ID:      firstNonEmpty(r.Link, r.Title),
+			Title:   cleanText(r.Title, 0),
+			Summary: cleanText(r.ShortDesc, newsSummaryLength),
+			Link:    r.Link,
+			Image:   r.Img,
+		})
+	}
+	return items, nil
+}
+
+// rssFeed RSS 2.0
+type rssFeed struct {
+	Items []struct {
+		Title       string `xml:"title"`
+		Link        string `xml:"link"`
+		GUID        string `xml:"guid"`
+		Description string `xml:"description"`
+		PubDate     string `xml:"pubDate"`
+		Enclosure   struct {
+			URL  string `xml:"url,attr"`
+			Type string `xml:"type,attr"`
+		} `xml:"enclosure"`
+		Media []struct {
+			URL string `xml:"url,attr"`
+		} `xml:"http://search.yahoo.com/mrss/ content"`
+	} `xml:"channel>item"`
+}
+
+// atomFeed Atom 1.0
+type atomFeed struct {
+	Entries []struct {
+		ID    string `xml:"id"`
+		Title string `xml:"title"`
+		Links []struct {
+			Href string `xml:"href,attr"`
+			Rel  string `xml:"rel,attr"`
+			Type string `xml:"type,attr"`
+		} `xml:"link"`
+		Summary   string `xml:"summary"`
+		Content   string `xml:"content"`
+		Updated   string `xml:"updated"`
+		Published string `xml:"published"`
+	} `xml:"entry"`
+}
+
+// parseFeed 解析 RSS 或 Atom
+func parseFeed(body []byte) ([]NewsItem, error) {
+	var root struct {
+		XMLName xml.Name
+	}
+	if err := xml.Unmarshal(body, &root); err != nil {
+		return nil, fmt.Errorf("解析新闻源失败: %w", err)
+	}
+
+	var items []NewsItem
+	switch root.XMLName.Local {
+	case "rss":
+		var feed rssFeed
+		if err := xml.Unmarshal(body, &feed); err != nil {
+			return nil, fmt.Errorf("解析 RSS 失败: %w", err)
+		}
+		for _, it := range feed.Items {
+			image := ""
+			if strings.HasPrefix(it.Enclosure.Type, "image/") {
+				image = it.Enclosure.URL
+			}
+			if image == "" && len(it.Media) > 0 {
+				image = it.Media[0].URL
+			}
+			if image == "" {
+				image = findImage(it.Description)
+			}
+			items = append(items, NewsItem{
+				ID:        firstNonEmpty(it.GUID, it.Link, it.Title),
+				Title:     cleanText(it.Title, 0),
+				Summary:   cleanText(it.Description, newsSummaryLength),
+				Link:      strings.TrimSpace(it.Link),
+				Image:     image,
+				Published: parseFeedTime(it.PubDate),
+			})
+		}
+	case "feed":
+		var feed atomFeed
+		if err := xml.Unmarshal(body, &feed); err != nil {
+			return nil, fmt.Errorf("解析 Atom 失败: %w", err)
+		}
+		for _, e := range feed.Entries {
+			var link, image string
+			for _, l := range e.Links {
+				switch {
+				case l.Rel == "enclosure" && strings.HasPrefix(l.Type, "image/"):
+					image = l.Href
+				case (l.Rel == "" || l.Rel == "alternate") && link == "":
+					link = l.Href
+				}
+			}
+			summary := firstNonEmpty(e.Summary, e.Content)
+			if image == "" {
+				image = findImage(firstNonEmpty(e.Content, e.Summary))
+			}
+			items = append(items, NewsItem{
+				ID:        firstNonEmpty(e.ID, link, e.Title),
+				Title:     cleanText(e.Title, 0),
+				Summary:   cleanText(summary, newsSummaryLength),
+				Link:      link,
+				Image:     image,
+				Published: parseFeedTime(firstNonEmpty(e.Published, e.Updated)),
+			})
+		}
+	default:
+		return nil, fmt.Errorf("不支持的新闻源格式: %s", root.XMLName.Local)
+	}
+	return items, nil
+}
+
+// parseFeedTime 解析 RSS/Atom 中的时间，失败时返回零值
+func parseFeedTime(s string) time.Time {
+	s = strings.TrimSpace(s)
+	for _, layout := range []string{time.RFC3339, time.RFC1123Z, time.RFC1123, "Mon, 2 Jan 2006 15:04:05 -0700", "Mon, 2 Jan 2006 15:04:05 MST"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// findImage 从 HTML 中找出第一张图片
+func findImage(s string) string {
+	if m := htmlImgPattern.FindStringSubmatch(s); m != nil {
+		return html.UnescapeString(m[1])
+	}
+	return ""
+}
+
+// cleanText 去除 HTML 标签与多余空白，limit 大于 0 时截断到指定字符数
+func cleanText(s string, limit int) string {
+	s = html.UnescapeString(htmlTagPattern.ReplaceAllString(s, " "))
+	s = strings.TrimSpace(spacePattern.ReplaceAllString(s, " "))
+	if runes := []rune(s); limit > 0 && len(runes) > limit {
+		s = string(runes[:limit]) + "…"
+	}
+	return s
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// FormatNewsItem 格式化新闻的文字部分
+func FormatNewsItem(item NewsItem) string {
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("【Apex 新闻】%s\n", item.Title))
+	if !item.Published.IsZero() {
+		output.WriteString(item.Published.Local().Format("2006-01-02 15:04") + "\n")
+	}
+	if item.Summary != "" {
+		output.WriteString(item.Summary + "\n")
+	}
+	if item.Link != "" {
+		output.WriteString(item.Link)
+	}
+	return strings.TrimRight(output.String(), "\n")
+}
+
+// NewsSeenData 已推送新闻的记录，与玩家绑定数据共用同一个数据库
+type NewsSeenData struct {
+	players *PlayerData
+}
+
+var NewsSeen = NewsSeenData{players: &Players}
+
+// FilterUnseen 记录全部新闻为已读，并返回此前未见过的新闻（保持原顺序）；首次运行时只记录不返回，避免推送旧闻
+func (n NewsSeenData) FilterUnseen(items []NewsItem) ([]NewsItem, error) {
+	p := n.players
+	p.Lock.Lock()
+	defer p.Lock.Unlock()
+
+	if err := p.ensureInit(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("开始事务失败: %w", err)
+	}
+	defer tx.Rollback()
+
+	var total int
+	if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM news_seen").Scan(&total); err != nil {
+		return nil, fmt.Errorf("读取新闻记录失败: %w", err)
+	}
+
+	now := time.Now().Unix()
+	var unseen []NewsItem
+	for _, item := range items {
+		res, err := tx.ExecContext(ctx, "INSERT OR IGNORE INTO news_seen (id, seen_at) VALUES (?, ?)", item.ID, now)
+		if err != nil {
+			return nil, fmt.Errorf("保存新闻记录失败: %w", err)
+		}
+		if affected, _ := res.RowsAffected(); affected > 0 && total > 0 {
+			unseen = append(unseen, item)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM news_seen WHERE seen_at < ?",
+		time.Now().Add(-newsSeenRetention).Unix()); err != nil {
+		return nil, fmt.Errorf("清理新闻记录失败: %w", err)
+	}
+	return unseen, tx.Commit()
+}
+
+// StartNewsPoller 在后台定期检查新闻，有新内容且存在订阅的群时调用 notify
+func StartNewsPoller(ctx context.Context, notify func(ctx context.Context, items []NewsItem, groups []string)) {
+	go func() {
+		for {
+			pollNews(ctx, notify)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(newsPollInterval):
+			}
+		}
+	}()
+}
+
+func pollNews(ctx context.Context, notify func(ctx context.Context, items []NewsItem, groups []string)) {
+	items, err := FetchNews(ctx)
+	if err != nil {
+		botlog.Warnf("获取新闻失败: %v", err)
+		return
+	}
+	unseen, err := NewsSeen.FilterUnseen(items)
+	if err != nil {
+		botlog.Warnf("%v", err)
+		return
+	}
+	if len(unseen) == 0 {
+		return
+	}
+	if len(unseen) > newsMaxPushItems {
+		unseen = unseen[:newsMaxPushItems]
+	}
+
+	groups, err := GroupSettings.GroupsWith(GroupSettingNews, "1")
+	if err != nil {
+		botlog.Warnf("读取新闻订阅失败: %v", err)
+		return
+	}
+	if len(groups) > 0 {
+		notify(ctx, unseen, groups)
+	}
+}
@@ -0,0 +1,106 @@
+package apexapi
+
+import "testing"
+
+const sampleRSS = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:media="http://search.yahoo.com/mrss/">
+<channel>
+  <title>Apex News</title>
+  <item>
+    <title>Patch Notes &amp; Update</title>
+    <link>https://example.com/patch</link>
+    <guid>patch-1</guid>
+    <pubDate>Tue, 14 Oct 2025 17:00:00 +0000</pubDate>
+    <description><![CDATA[<p>Balance <b>changes</b></p><img src="https://example.com/a.jpg">]]></description>
+  </item>
+  <item>
+    <title>Event</title>
+    <link>https://example.com/event</link>
+    <enclosure url="https://example.com/event.png" type="image/png" length="1"/>
+    <description>New event</description>
+  </item>
+</channel>
+</rss>`
+
+const sampleAtom = `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Apex</title>
+  <entry>
+    <id>tag:example.com,2025:1</id>
+    <title>Season Launch</title>
+    <link rel="alternate" href="https://example.com/season"/>
+    <updated>2025-11-04T17:00:00Z</updated>
+    <summary>New season is here</summary>
+  </entry>
+</feed>`
+
+func TestParseNewsRSS(t *testing.T) {
+	items, err := parseNews([]byte(sampleRSS))
+	if err != nil {
+		t.Fatalf("parseNews: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("len = %d, want 2", len(items))
+	}
+	first := items[0]
+	if first.ID != "patch-1" || first.Title != "Patch Notes & Update" || first.Summary != "Balance changes" {
+		t.Errorf("first item = %+v", first)
+	}
+	if first.Image != "https://example.com/a.jpg" || first.Published.IsZero() {
+		t.Errorf("first image/published = %q %v", first.Image, first.Published)
+	}
+	if items[1].ID != "https://example.com/event" || items[1].Image != "https://example.com/event.png" {
+		t.Errorf("second item = %+v", items[1])
+	}
+}
+
+func TestParseNewsAtom(t *testing.T) {
+	items, err := parseNews([]byte(sampleAtom))
+	if err != nil {
+		t.Fatalf("parseNews: %v", err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("len = %d, want 1", len(items))
+	}
+	if items[0].ID != "tag:example.com,2025:1" || items[0].Link != "https://example.com/season" || items[0].Summary != "New season is here" {
+		t.Errorf("item = %+v", items[0])
+	}
+}
+
+func TestParseNewsGateway(t *testing.T) {
+	items, err := parseNews([]byte(`[{"title":"A","link":"https://example.com/a","img":"https://example.com/a.png","short_desc":"desc"}]`))
+	if err != nil {
+		t.Fatalf("parseNews: %v", err)
+	}
+	if len(items) != 1 || items[0].ID != "https://example.com/a" || items[0].Image != "https://example.com/a.png" {
+		t.Errorf("items = %+v", items)
+	}
+}
+
+func TestCleanTextTruncate(t *testing.T) {
+	if got := cleanText("<p>一二三四五</p>", 3); got != "一二三…" {
+		t.Errorf("cleanText = %q", got)
+	}
+}
+
+func TestNewsSeenFilterUnseen(t *testing.T) {
+	seen := NewsSeenData{players: newTestPlayerData(t)}
+	first := []NewsItem{{ID: "a"}, {ID: "b"}}
+
+	// 首次运行只记录，不推送旧闻
+	unseen, err := seen.FilterUnseen(first)
+	if err != nil {
+		t.Fatalf("FilterUnseen: %v", err)
+	}
+	if len(unseen) != 0 {
+		t.Fatalf("first run unseen = %v, want none", unseen)
+	}
+
+	unseen, err = seen.FilterUnseen([]NewsItem{{ID: "c"}, {ID: "a"}})
+	if err != nil {
+		t.Fatalf("FilterUnseen: %v", err)
+	}
+	if len(unseen) != 1 || unseen[0].ID != "c" {
+		t.Errorf("unseen = %v, want [c]", unseen)
+	}
+}
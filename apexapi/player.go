@@ -3,10 +3,10 @@ package apexapi
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"net/http"
-	"net/url"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -37,17 +37,19 @@ func getLegendsTranslator() *tools.Translator {
 
 // PlayerResponse API 响应结构
 type PlayerResponse struct {
-	Global  GlobalInfo            `json:"global"`
-	Legends map[string]LegendInfo `json:"legends"`
+	Global   GlobalInfo                `json:"global"`
+	Realtime RealtimeInfo              `json:"realtime"`
+	Legends  LegendsInfo               `json:"legends"`
+	Total    map[string]LegendStatItem `json:"total"` // 全部传奇合计的数据（击杀、伤害等），键为接口中的 key
 }
 
 // GlobalInfo 全局玩家信息
 type GlobalInfo struct {
-	Name     string    `json:"name"`
-	UID      any       `json:"uid"`       // 支持 string 或 int64
-	Platform string    `json:"platform"`
-	Level    float64   `json:"level"`
-	Rank     RankInfo  `json:"rank"`
+	Name     string   `json:"name"`
+	UID      any      `json:"uid"` // 支持 string 或 int64
+	Platform string   `json:"platform"`
+	Level    float64  `json:"level"`
+	Rank     RankInfo `json:"rank"`
 }
 
 // RankInfo 段位信息
@@ -57,16 +59,23 @@ type RankInfo struct {
 	RankScore float64 `json:"rankScore"`
 }
 
-// LegendInfo 传奇信息
-type LegendInfo struct {
-	Selected LegendSelected `json:"selected"`
+// LegendsInfo 传奇信息
+type LegendsInfo struct {
+	Selected LegendSelected          `json:"selected"`
+	All      map[string]LegendDetail `json:"all"` // 全部传奇，键为英文名
 }
 
 // LegendSelected 当前选择的传奇
 type LegendSelected struct {
-	LegendName string                 `json:"LegendName"`
-	Data      []LegendStatItem       `json:"data"`
-	ImgAssets LegendImgAssets        `json:"ImgAssets"`
+	LegendName string           `json:"LegendName"`
+	Data       []LegendStatItem `json:"data"`
+	ImgAssets  LegendImgAssets  `json:"ImgAssets"`
+}
+
+// LegendDetail 单个传奇的数据，未装备追踪器时 Data 为空
+type LegendDetail struct {
+	Data      []LegendStatItem `json:"data"`
+	ImgAssets LegendImgAssets  `json:"ImgAssets"`
 }
 
 // LegendImgAssets 传奇图片资源
@@ -79,6 +88,7 @@ type LegendImgAssets struct {
 type LegendStatItem struct {
 	Name  string `json:"name"`
 	Value any    `json:"value"` // 支持字符串或数字
+	Key   string `json:"key,omitempty"`
 }
 
 // DisplayChangedOption 显示变化选项
@@ -89,57 +99,40 @@ type DisplayChangedOption struct {
 
 // ============ API 调用函数 ============
 
-// GetPlayerData 获取玩家数据（返回结构体）
+// GetPlayerData 从数据来源获取玩家完整数据（包含全部传奇与合计数据）
 func GetPlayerData(ctx context.Context, EAID string) (*PlayerResponse, error) {
-	params := url.Values{}
-	params.Add("userName", EAID)
-	params.Add("userPlatform", "PC")
-	params.Add("qt", "stats-single-legend")
-	urlStr := "https://lil2-gateway.apexlegendsstatus.com/gateway.php?" + params.Encode()
-
-	if ApiConf.ApiToken == "" {
-		return nil, ErrEmptyAPIToken
-	}
-
-	req, err := http.NewRequestWithContext(ctx, "POST", urlStr, nil)
-	if err != nil {
-		return nil, fmt.Errorf("%w: %v", ErrRequestCreateFailed, err)
-	}
+	return getPlayerProvider().Player(ctx, EAID)
+}
 
-	resp, err := GetHTTPClient(10 * time.Second).Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("%w: %v", ErrRequestFailed, err)
+// playerLookupError 转换玩家接口的错误：该接口返回 404 表示玩家不存在
+func playerLookupError(err error) error {
+	var apiErr *APIError
+	if errors.Is(err, ErrNotFound) && errors.As(err, &apiErr) {
+		return &APIError{Kind: KindNotFound, StatusCode: apiErr.StatusCode, Err: ErrNoPlayerFound}
 	}
-	defer resp.Body.Close()
+	return err
+}
 
-	limitReader := io.LimitReader(resp.Body, 64<<10) // 最大 64K
-	body, err := io.ReadAll(limitReader)
-	if err != nil {
-		return nil, ErrReadResponseFailed
+// parsePlayerResponse 解析玩家数据，接口在状态码 200 时也可能通过 Error 字段返回错误
+func parsePlayerResponse(body []byte) (*PlayerResponse, error) {
+	var errRes struct {
+		Error string `json:"Error"`
 	}
-
-	if resp.StatusCode == http.StatusOK {
-		var statRes struct {
-			StatsAPI *PlayerResponse `json:"statsAPI"`
-		}
-		err := json.Unmarshal(body, &statRes)
-		if err != nil {
-			return nil, fmt.Errorf("%w: %v", ErrInvalidJSON, err)
-		}
-
-		if statRes.StatsAPI == nil {
-			return nil, fmt.Errorf("%w: statsAPI 为空", ErrInvalidJSON)
+	if err := json.Unmarshal(body, &errRes); err == nil && errRes.Error != "" {
+		if strings.Contains(strings.ToLower(errRes.Error), "not found") {
+			return nil, ErrNoPlayerFound
 		}
-
-		// 检查 API 返回的错误
-		// 注意：这里需要根据实际的 API 响应结构调整
-		return statRes.StatsAPI, nil
+		return nil, UpstreamError(errRes.Error)
 	}
 
-	if strings.Contains(string(body), "API key doesn't exist !") && resp.StatusCode == 404 {
-		return nil, ErrWrongAPIToken
+	var player PlayerResponse
+	if err := json.Unmarshal(body, &player); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidJSON, err)
+	}
+	if player.Global.Name == "" && player.Global.UID == nil {
+		return nil, fmt.Errorf("%w: global 为空", ErrInvalidJSON)
 	}
-	return nil, ErrStatusCode(resp.StatusCode)
+	return &player, nil
 }
 
 // GetPlayerRank 从结构体获取段位分数
@@ -152,8 +145,8 @@ func GetPlayerRank(player *PlayerResponse) (int, error) {
 
 // ============ 格式化输出函数 ============
 
-// FormatPlayerData 美观地格式化玩家数据
-func FormatPlayerData(player *PlayerResponse, change ...DisplayChangedOption) string {
+// FormatPlayerData 美观地格式化玩家数据，predator 为 nil 时不展示与猎杀线的差距
+func FormatPlayerData(player *PlayerResponse, predator Predator, change ...DisplayChangedOption) string {
 	if player == nil {
 		return "玩家数据为空"
 	}
@@ -183,30 +176,49 @@ func FormatPlayerData(player *PlayerResponse, change ...DisplayChangedOption) st
 
 	// 段位信息
 	if player.Global.Rank.RankName != "" {
-		output.WriteString(fmt.Sprintf("段位: %s %v\n", player.Global.Rank.RankName, player.Global.Rank.RankDiv))
-		output.WriteString(fmt.Sprintf("段位分数: %.0f\n", player.Global.Rank.RankScore))
+		score := int(player.Global.Rank.RankScore)
+		output.WriteString(fmt.Sprintf("段位: %s %v\n", GetRankTierName(player.Global.Rank.RankName), player.Global.Rank.RankDiv))
+		output.WriteString(fmt.Sprintf("段位分数: %d\n", score))
+		output.WriteString(FormatRankProgress(score))
+		output.WriteString(FormatPredatorDistance(player, predator))
 
 		if len(change) > 0 {
-			deltaScore := int(player.Global.Rank.RankScore) - change[0].LastScore
+			deltaScore := score - change[0].LastScore
 			if deltaScore != 0 {
 				output.WriteString(fmt.Sprintf("段位分数变化: %+d\n", deltaScore))
-				output.WriteString(fmt.Sprintf("当前用户上次查询时间: %s\n", change[0].LastTime.Format("2006-01-02 15:04:05")))
+				output.WriteString(FormatRankChange(change[0].LastScore, score))
+				output.WriteString(fmt.Sprintf("上次记录时间: %s\n", change[0].LastTime.Format("2006-01-02 15:04:05")))
 			}
 		}
 	}
 
+	// 合计数据
+	if totals := formatStatItems(sortedTotals(player.Total)); totals != "" {
+		output.WriteString("\n合计数据:\n")
+		output.WriteString(totals)
+	}
+
 	// 当前传奇
-	if selected, ok := player.Legends["selected"]; ok {
-		legendName := GetLegendName(selected.Selected.LegendName)
-		output.WriteString(fmt.Sprintf("\n当前选择的传奇: %s\n", legendName))
+	selected := player.Legends.Selected
+	if selected.LegendName != "" {
+		output.WriteString(fmt.Sprintf("\n当前选择的传奇: %s\n", GetLegendName(selected.LegendName)))
 	}
 
 	// 传奇数据
 	output.WriteString("传奇数据:\n")
-	if selected, ok := player.Legends["selected"]; ok {
-		for i, stat := range selected.Selected.Data {
-			output.WriteString(fmt.Sprintf("  %d. %s: %v\n", i+1, stat.Name, stat.Value))
+	output.WriteString(formatStatItems(selected.Data))
+
+	// 其他装备了追踪器的传奇
+	var others []string
+	for _, name := range player.LegendNames() {
+		if name == selected.LegendName || len(player.Legends.All[name].Data) == 0 {
+			continue
 		}
+		others = append(others, GetLegendName(name))
+	}
+	if len(others) > 0 {
+		output.WriteString(fmt.Sprintf("\n其他有数据的传奇: %s\n", strings.Join(others, "、")))
+		output.WriteString("使用 /a查询 <EAID> 传奇 <名称> 查看单个传奇的数据\n")
 	}
 
 	output.WriteString("=========================\n")
@@ -214,6 +226,112 @@ func FormatPlayerData(player *PlayerResponse, change ...DisplayChangedOption) st
 	return output.String()
 }
 
+// FormatLegendData 格式化单个传奇的数据，legend 为英文名
+func FormatLegendData(player *PlayerResponse, legend string) string {
+	if player == nil {
+		return "玩家数据为空"
+	}
+
+	data := player.LegendData(legend)
+
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("\n== %s 的 %s 数据 ==\n", player.Global.Name, GetLegendName(legend)))
+	if len(data) == 0 {
+		output.WriteString("该传奇没有装备追踪器，暂无数据\n")
+	} else {
+		output.WriteString(formatStatItems(data))
+	}
+	output.WriteString("=========================\n")
+	return output.String()
+}
+
+// formatStatItems 按击杀、伤害、胜场分组格式化数据项
+func formatStatItems(items []LegendStatItem) string {
+	groups := groupStatItems(items)
+	var output strings.Builder
+	for _, c := range statCategories {
+		if len(groups[c]) == 0 {
+			continue
+		}
+		output.WriteString(fmt.Sprintf("  【%s】\n", c))
+		for _, stat := range groups[c] {
+			output.WriteString(fmt.Sprintf("    %s: %s\n", stat.DisplayName(), stat.DisplayValue()))
+		}
+	}
+	return output.String()
+}
+
+// sortedTotals 按 key 排序合计数据，跳过接口以 -1 表示不可用的项
+func sortedTotals(total map[string]LegendStatItem) []LegendStatItem {
+	keys := make([]string, 0, len(total))
+	for key := range total {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	items := make([]LegendStatItem, 0, len(keys))
+	for _, key := range keys {
+		item := total[key]
+		if fmt.Sprint(item.Value) == "-1" {
+			continue
+		}
+		if item.Key == "" {
+			item.Key = key
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// LegendNames 返回全部传奇的英文名（按名称排序）
+func (p *PlayerResponse) LegendNames() []string {
+	names := make([]string, 0, len(p.Legends.All)+1)
+	for name := range p.Legends.All {
+		names = append(names, name)
+	}
+	// 网关数据只包含当前选择的传奇
+	if selected := p.Legends.Selected.LegendName; selected != "" && !slices.Contains(names, selected) {
+		names = append(names, selected)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// HasAllLegends 数据中是否包含全部传奇（网关数据只包含当前选择的传奇）
+func (p *PlayerResponse) HasAllLegends() bool {
+	return len(p.Legends.All) > 0
+}
+
+// LegendData 获取传奇的追踪器数据，当前选择的传奇优先使用 selected 中的数据
+func (p *PlayerResponse) LegendData(legend string) []LegendStatItem {
+	if legend == p.Legends.Selected.LegendName && len(p.Legends.Selected.Data) > 0 {
+		return p.Legends.Selected.Data
+	}
+	return p.Legends.All[legend].Data
+}
+
+// FindLegend 通过中文名或英文名（忽略大小写）查找传奇，返回英文名
+func (p *PlayerResponse) FindLegend(name string) (string, bool) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", false
+	}
+	if trans := getLegendsTranslator(); trans != nil {
+		if key, ok := trans.ReverseLookup(name); ok {
+			name = key
+		}
+	}
+	for _, legend := range p.LegendNames() {
+		if strings.EqualFold(legend, name) {
+			return legend, true
+		}
+	}
+	if strings.EqualFold(p.Legends.Selected.LegendName, name) {
+		return p.Legends.Selected.LegendName, true
+	}
+	return "", false
+}
+
 // GetLegendName 获取传奇名称（中文）
 func GetLegendName(legendName string) string {
 	trans := getLegendsTranslator()
@@ -231,7 +349,7 @@ func DisplayPlayerData(data string, change ...DisplayChangedOption) string {
 	if err := json.Unmarshal([]byte(data), &player); err != nil {
 		return fmt.Sprintf("解析玩家数据出错: %v\n", err)
 	}
-	return FormatPlayerData(&player, change...)
+	return FormatPlayerData(&player, nil, change...)
 }
 
 // GetPlayerRank 向后兼容的获取段位函数（接受 JSON 字符串）
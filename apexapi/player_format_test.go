@@ -0,0 +1,130 @@
+package apexapi
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const samplePlayerJSON = `{
+	"global": {"name": "Tester", "uid": 1234567890, "platform": "PC", "level": 500,
+		"rank": {"rankName": "Diamond", "rankDiv": 4, "rankScore": 11200}},
+	"legends": {
+		"selected": {"LegendName": "Wraith", "data": [{"name": "BR Kills", "value": 321, "key": "kills"}]},
+		"all": {
+			"Wraith": {"data": [{"name": "BR Kills", "value": 321, "key": "kills"}]},
+			"Bloodhound": {"data": [{"name": "BR Damage", "value": 98765, "key": "damage"}]},
+			"Lifeline": {}
+		}
+	},
+	"total": {
+		"kills": {"name": "BR Kills", "value": 1500},
+		"kd": {"name": "KD", "value": "-1"}
+	}
+}`
+
+func TestParsePlayerResponse(t *testing.T) {
+	player, err := parsePlayerResponse([]byte(samplePlayerJSON))
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	if len(player.Legends.All) != 3 {
+		t.Fatalf("传奇数量为 %d，期望 3", len(player.Legends.All))
+	}
+
+	totals := sortedTotals(player.Total)
+	if len(totals) != 1 || totals[0].Key != "kills" {
+		t.Errorf("合计数据应跳过不可用的 kd: %+v", totals)
+	}
+
+	legend, ok := player.FindLegend("bloodhound")
+	if !ok || legend != "Bloodhound" {
+		t.Fatalf("查找传奇失败: %q %v", legend, ok)
+	}
+	if msg := FormatLegendData(player, legend); !strings.Contains(msg, "98,765") {
+		t.Errorf("传奇数据缺少追踪器: %s", msg)
+	}
+	if msg := FormatLegendData(player, "Lifeline"); !strings.Contains(msg, "暂无数据") {
+		t.Errorf("无追踪器的传奇应提示暂无数据: %s", msg)
+	}
+	if _, ok := player.FindLegend("不存在"); ok {
+		t.Error("不存在的传奇不应被找到")
+	}
+}
+
+func TestSelectedOnlyPlayerResponse(t *testing.T) {
+	// 网关只返回当前选择的传奇
+	player, err := parsePlayerResponse([]byte(`{
+		"global": {"name": "Tester", "uid": 1, "level": 100},
+		"legends": {"selected": {"LegendName": "Wraith", "data": [{"name": "BR Kills", "value": 321, "key": "kills"}]}}
+	}`))
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	if player.HasAllLegends() {
+		t.Error("只有所选传奇时 HasAllLegends 应为 false")
+	}
+	if names := player.LegendNames(); len(names) != 1 || names[0] != "Wraith" {
+		t.Errorf("LegendNames = %v", names)
+	}
+	if name, ok := player.FindLegend("wraith"); !ok || name != "Wraith" {
+		t.Errorf("FindLegend = %q, %v", name, ok)
+	}
+	if out := FormatLegendData(player, "Wraith"); !strings.Contains(out, "321") {
+		t.Errorf("所选传奇的数据缺失:\n%s", out)
+	}
+}
+
+func TestParsePlayerResponseError(t *testing.T) {
+	_, err := parsePlayerResponse([]byte(`{"Error": "Player Nobody not found"}`))
+	if !errors.Is(err, ErrNoPlayerFound) {
+		t.Errorf("期望 ErrNoPlayerFound，得到 %v", err)
+	}
+}
+
+func TestFormatNumber(t *testing.T) {
+	tests := map[float64]string{
+		0:         "0",
+		999:       "999",
+		1000:      "1,000",
+		1234567:   "1,234,567",
+		-98765:    "-98,765",
+		1234.5:    "1,234.50",
+		0.25:      "0.25",
+		1.996:     "2",
+		999.999:   "1,000",
+		1.994:     "1.99",
+		-0.001:    "0",
+		100000000: "100,000,000",
+	}
+	for n, want := range tests {
+		if got := FormatNumber(n); got != want {
+			t.Errorf("FormatNumber(%v) = %s，期望 %s", n, got, want)
+		}
+	}
+}
+
+func TestStatItemCategory(t *testing.T) {
+	tests := []struct {
+		item LegendStatItem
+		want StatCategory
+	}{
+		{LegendStatItem{Name: "BR Kills", Key: "kills"}, StatKills},
+		{LegendStatItem{Name: "Winning kills"}, StatKills},
+		{LegendStatItem{Name: "BR Damage", Key: "damage"}, StatDamage},
+		{LegendStatItem{Name: "Season 20 Wins", Key: "wins_season_20"}, StatWins},
+		{LegendStatItem{Name: "Revives"}, StatOther},
+	}
+	for _, tt := range tests {
+		if got := tt.item.Category(); got != tt.want {
+			t.Errorf("%s 的分类为 %s，期望 %s", tt.item.Name, got, tt.want)
+		}
+	}
+
+	if n, ok := (LegendStatItem{Value: "12,345"}).Number(); !ok || n != 12345 {
+		t.Errorf("解析带千分位的字符串失败: %v %v", n, ok)
+	}
+	if _, ok := (LegendStatItem{Value: "N/A"}).Number(); ok {
+		t.Error("非数字字符串不应解析成功")
+	}
+}
@@ -26,6 +26,55 @@ const (
 			last_rank_score INTEGER NOT NULL
 		);
 		CREATE INDEX IF NOT EXISTS idx_eaid ON player_bindings(ea_id);
+		CREATE TABLE IF NOT EXISTS group_settings (
+			group_id TEXT NOT NULL,
+			setting_key TEXT NOT NULL,
+			value TEXT NOT NULL,
+			PRIMARY KEY (group_id, setting_key)
+		);
+		CREATE TABLE IF NOT EXISTS map_rotation_slots (
+			mode TEXT NOT NULL,
+			code TEXT NOT NULL,
+			start_time INTEGER NOT NULL,
+			end_time INTEGER NOT NULL,
+			PRIMARY KEY (mode, start_time)
+		);
+		CREATE TABLE IF NOT EXISTS group_members (
+			group_id TEXT NOT NULL,
+			qq_id TEXT NOT NULL,
+			last_seen INTEGER NOT NULL,
+			PRIMARY KEY (group_id, qq_id)
+		);
+		CREATE INDEX IF NOT EXISTS idx_group_members_qq ON group_members(qq_id);
+		CREATE TABLE IF NOT EXISTS rank_history (
+			qq_id TEXT NOT NULL,
+			rank_score INTEGER NOT NULL,
+			recorded_at INTEGER NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS idx_rank_history_qq ON rank_history(qq_id, recorded_at);
+		CREATE TABLE IF NOT EXISTS lfg_entries (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			group_id TEXT NOT NULL,
+			mode TEXT NOT NULL,
+			rank_req TEXT NOT NULL,
+			creator TEXT NOT NULL,
+			created_at INTEGER NOT NULL,
+			expires_at INTEGER NOT NULL,
+			closed INTEGER NOT NULL DEFAULT 0
+		);
+		CREATE INDEX IF NOT EXISTS idx_lfg_group ON lfg_entries(group_id, closed, expires_at);
+		CREATE TABLE IF NOT EXISTS lfg_members (
+			entry_id INTEGER NOT NULL,
+			qq_id TEXT NOT NULL,
+			ea_id TEXT NOT NULL,
+			rank_score INTEGER NOT NULL,
+			joined_at INTEGER NOT NULL,
+			PRIMARY KEY (entry_id, qq_id)
+		);
+		CREATE TABLE IF NOT EXISTS news_seen (
+			id TEXT PRIMARY KEY,
+			seen_at INTEGER NOT NULL
+		);
 		PRAGMA journal_mode=WAL;
 		PRAGMA synchronous=NORMAL;
 		PRAGMA busy_timeout=5000;
@@ -42,7 +91,7 @@ type PlayerBindingData struct {
 }
 
 type PlayerData struct {
-	db       *sql.DB
+	db       *instrumentedDB
 	Lock     sync.RWMutex
 	initOnce sync.Once
 	initErr  error
@@ -81,7 +130,7 @@ func (p *PlayerData) Init() error {
 			return
 		}
 
-		p.db = db
+		p.db = &instrumentedDB{db}
 	})
 
 	return p.initErr
@@ -95,6 +144,17 @@ func (p *PlayerData) Close() error {
 	return nil
 }
 
+// Ping 检查数据库连接是否可用
+func (p *PlayerData) Ping(ctx context.Context) error {
+	p.Lock.RLock()
+	defer p.Lock.RUnlock()
+
+	if err := p.ensureInit(); err != nil {
+		return err
+	}
+	return p.db.PingContext(ctx)
+}
+
 // 检查数据库是否已初始化
 func (p *PlayerData) ensureInit() error {
 	if p.db == nil {
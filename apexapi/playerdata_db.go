@@ -0,0 +1,61 @@
+package apexapi
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// instrumentedDB 包装 *sql.DB，记录每条语句的耗时
+type instrumentedDB struct {
+	*sql.DB
+}
+
+func (db *instrumentedDB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	defer observeSQLite("exec", time.Now())
+	return db.DB.ExecContext(ctx, query, args...)
+}
+
+func (db *instrumentedDB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	defer observeSQLite("query", time.Now())
+	return db.DB.QueryContext(ctx, query, args...)
+}
+
+func (db *instrumentedDB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	defer observeSQLite("query_row", time.Now())
+	return db.DB.QueryRowContext(ctx, query, args...)
+}
+
+func (db *instrumentedDB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*instrumentedTx, error) {
+	defer observeSQLite("begin", time.Now())
+	tx, err := db.DB.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &instrumentedTx{tx}, nil
+}
+
+// instrumentedTx 包装 *sql.Tx，记录事务内每条语句的耗时
+type instrumentedTx struct {
+	*sql.Tx
+}
+
+func (tx *instrumentedTx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	defer observeSQLite("exec", time.Now())
+	return tx.Tx.ExecContext(ctx, query, args...)
+}
+
+func (tx *instrumentedTx) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	defer observeSQLite("query", time.Now())
+	return tx.Tx.QueryContext(ctx, query, args...)
+}
+
+func (tx *instrumentedTx) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	defer observeSQLite("query_row", time.Now())
+	return tx.Tx.QueryRowContext(ctx, query, args...)
+}
+
+func (tx *instrumentedTx) Commit() error {
+	defer observeSQLite("commit", time.Now())
+	return tx.Tx.Commit()
+}
@@ -0,0 +1,184 @@
+package apexapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	botlog "github.com/tencent-connect/botgo/log"
+)
+
+// 猎杀线数据的缓存时长（接口数据本身约每小时更新）
+const predatorCacheDuration = 15 * time.Minute
+
+// 猎杀线获取失败后，在该时间内直接返回上次的错误，避免每次查询都等待上游超时
+const predatorRetryInterval = time.Minute
+
+// 平台展示顺序
+var predatorPlatforms = []string{"PC", "PS4", "X1", "SWITCH"}
+
+// PredatorCutoff 单个平台的猎杀线
+type PredatorCutoff struct {
+	FoundRank            int      `json:"foundRank"` // 第 750 名时为满员
+	Val                  int      `json:"val"`       // 猎杀线分数
+	UID                  any      `json:"uid"`       // 支持 string 或 int64
+	UpdateTimestamp      UnixTime `json:"updateTimestamp"`
+	TotalMastersAndPreds int      `json:"totalMastersAndPreds"`
+}
+
+// Predator 各平台的排位猎杀线，键为平台代码
+type Predator map[string]PredatorCutoff
+
+var (
+	cachedPredator   Predator
+	predatorCachedAt time.Time
+	predatorErr      error     // 最近一次获取的错误，成功时为 nil
+	predatorErrAt    time.Time // 最近一次获取失败的时间
+	predatorLock     sync.RWMutex
+)
+
+// platformName 平台的展示名
+func platformName(platform string) string {
+	switch strings.ToUpper(platform) {
+	case "PS4":
+		return "PlayStation"
+	case "X1":
+		return "Xbox"
+	case "SWITCH":
+		return "Switch"
+	default:
+		return platform
+	}
+}
+
+// GetPredator 获取排位猎杀线（带缓存）
+func GetPredator() (Predator, error) {
+	predatorLock.RLock()
+	if cachedPredator != nil && time.Since(predatorCachedAt) < predatorCacheDuration {
+		result := cachedPredator
+		predatorLock.RUnlock()
+		return result, nil
+	}
+	if predatorErr != nil && time.Since(predatorErrAt) < predatorRetryInterval {
+		err := predatorErr
+		predatorLock.RUnlock()
+		return nil, err
+	}
+	predatorLock.RUnlock()
+
+	return GetPredatorFromAPI()
+}
+
+// GetPredatorFromAPI 从数据来源获取排位猎杀线（不带缓存）
+func GetPredatorFromAPI() (Predator, error) {
+	predator, err := GetStatsProvider().Predator(context.Background())
+	if err != nil {
+		predatorLock.Lock()
+		predatorErr = err
+		predatorErrAt = time.Now()
+		predatorLock.Unlock()
+		return nil, err
+	}
+
+	predatorLock.Lock()
+	cachedPredator = predator
+	predatorCachedAt = time.Now()
+	predatorErr = nil
+	predatorLock.Unlock()
+
+	return predator, nil
+}
+
+// parsePredator 解析猎杀线数据（取排位分数 RP 部分）
+func parsePredator(body []byte) (Predator, error) {
+	var raw struct {
+		RP map[string]json.RawMessage `json:"RP"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidJSON, err)
+	}
+
+	predator := make(Predator)
+	for platform, data := range raw.RP {
+		var cutoff PredatorCutoff
+		if err := json.Unmarshal(data, &cutoff); err != nil {
+			// 单个平台数据异常时跳过
+			continue
+		}
+		predator[strings.ToUpper(platform)] = cutoff
+	}
+	if len(predator) == 0 {
+		return nil, fmt.Errorf("%w: 猎杀线数据为空", ErrInvalidJSON)
+	}
+	return predator, nil
+}
+
+// FormatPredator 格式化各平台的猎杀线
+func FormatPredator(p Predator) string {
+	var output strings.Builder
+	output.WriteString("当前排位猎杀线:\n")
+
+	var updated time.Time
+	for _, platform := range predatorPlatforms {
+		cutoff, ok := p[platform]
+		if !ok {
+			continue
+		}
+		output.WriteString(fmt.Sprintf("  %s: %s 分", platformName(platform), FormatNumber(float64(cutoff.Val))))
+		if cutoff.TotalMastersAndPreds > 0 {
+			output.WriteString(fmt.Sprintf("（大师及猎杀共 %s 人）", FormatNumber(float64(cutoff.TotalMastersAndPreds))))
+		}
+		output.WriteString("\n")
+		if t := time.Time(cutoff.UpdateTimestamp); t.After(updated) {
+			updated = t
+		}
+	}
+	if !updated.IsZero() {
+		output.WriteString(fmt.Sprintf("数据更新于 %s", updated.Local().Format("2006-01-02 15:04")))
+	}
+	return strings.TrimRight(output.String(), "\n")
+}
+
+// isMasterOrAbove 段位是否为大师或猎杀者
+func isMasterOrAbove(rankName string) bool {
+	name := strings.ToLower(rankName)
+	return strings.Contains(name, "master") || strings.Contains(name, "predator")
+}
+
+// GetPredatorForPlayer 大师及以上段位的玩家获取猎杀线，用于在玩家信息中展示差距；其他段位或获取失败时返回 nil
+func GetPredatorForPlayer(player *PlayerResponse) Predator {
+	if player == nil || !isMasterOrAbove(player.Global.Rank.RankName) {
+		return nil
+	}
+	predator, err := GetPredator()
+	if err != nil {
+		botlog.Warnf("获取猎杀线失败: %v", err)
+		return nil
+	}
+	return predator
+}
+
+// FormatPredatorDistance 大师及以上段位的玩家展示与猎杀线的差距，其他段位或没有猎杀线数据时返回空字符串
+func FormatPredatorDistance(player *PlayerResponse, predator Predator) string {
+	if player == nil || predator == nil || !isMasterOrAbove(player.Global.Rank.RankName) {
+		return ""
+	}
+
+	platform := strings.ToUpper(player.Global.Platform)
+	if platform == "" {
+		platform = "PC"
+	}
+	cutoff, ok := predator[platform]
+	if !ok || cutoff.Val <= 0 {
+		return ""
+	}
+
+	score := int(player.Global.Rank.RankScore)
+	if score >= cutoff.Val {
+		return fmt.Sprintf("猎杀线(%s): %s 分，已超过 %s 分\n", platformName(platform), FormatNumber(float64(cutoff.Val)), FormatNumber(float64(score-cutoff.Val)))
+	}
+	return fmt.Sprintf("猎杀线(%s): %s 分，还差 %s 分\n", platformName(platform), FormatNumber(float64(cutoff.Val)), FormatNumber(float64(cutoff.Val-score)))
+}
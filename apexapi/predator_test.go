@@ -0,0 +1,59 @@
+package apexapi
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPredator(t *testing.T) {
+	predator, err := parsePredator([]byte(`{"RP": {
+		"PC": {"foundRank": 750, "val": 25318, "uid": 1000, "updateTimestamp": 1760000000, "totalMastersAndPreds": 15234},
+		"PS4": {"foundRank": 750, "val": 21000, "uid": "2000", "updateTimestamp": 1760000000, "totalMastersAndPreds": 9000},
+		"SWITCH": "bad"
+	}}`))
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	if len(predator) != 2 || predator["PC"].Val != 25318 {
+		t.Fatalf("猎杀线数据错误: %+v", predator)
+	}
+	msg := FormatPredator(predator)
+	if !strings.Contains(msg, "PC: 25,318 分") || !strings.Contains(msg, "PlayStation: 21,000 分") {
+		t.Errorf("猎杀线格式错误: %s", msg)
+	}
+
+	player := &PlayerResponse{Global: GlobalInfo{Platform: "PC", Rank: RankInfo{RankName: "Master", RankScore: 20318}}}
+	if got := FormatPredatorDistance(player, predator); !strings.Contains(got, "还差 5,000 分") {
+		t.Errorf("与猎杀线的差距错误: %s", got)
+	}
+	if got := FormatPredatorDistance(player, nil); got != "" {
+		t.Errorf("没有猎杀线数据时不应显示: %s", got)
+	}
+	player.Global.Rank = RankInfo{RankName: "Diamond", RankScore: 14000}
+	if got := FormatPredatorDistance(player, predator); got != "" {
+		t.Errorf("大师以下不应显示猎杀线: %s", got)
+	}
+}
+
+func TestPredatorFailureCached(t *testing.T) {
+	predatorLock.Lock()
+	cachedPredator = nil
+	predatorErr, predatorErrAt = ErrServiceUnavailable, time.Now()
+	predatorLock.Unlock()
+	defer func() {
+		predatorLock.Lock()
+		predatorErr = nil
+		predatorLock.Unlock()
+	}()
+
+	// 失败后的重试间隔内不请求上游，直接返回上次的错误
+	if _, err := GetPredator(); !errors.Is(err, ErrServiceUnavailable) {
+		t.Errorf("GetPredator() err = %v, want %v", err, ErrServiceUnavailable)
+	}
+	player := &PlayerResponse{Global: GlobalInfo{Platform: "PC", Rank: RankInfo{RankName: "Master", RankScore: 20318}}}
+	if got := GetPredatorForPlayer(player); got != nil {
+		t.Errorf("获取失败时应返回 nil: %+v", got)
+	}
+}
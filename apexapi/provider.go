@@ -0,0 +1,182 @@
+package apexapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	botlog "github.com/tencent-connect/botgo/log"
+)
+
+// StatsProvider Apex 数据来源
+type StatsProvider interface {
+	// Name 数据来源名称，与配置中的 providers 对应
+	Name() string
+	// Player 获取玩家完整数据
+	Player(ctx context.Context, EAID string) (*PlayerResponse, error)
+	// MapRotation 获取地图轮换
+	MapRotation(ctx context.Context) (MapRotate, error)
+	// Crafting 获取制造器轮换
+	Crafting(ctx context.Context) (Crafting, error)
+	// Predator 获取排位猎杀线
+	Predator(ctx context.Context) (Predator, error)
+	// ServerStatus 获取各区域服务器状态
+	ServerStatus(ctx context.Context) (*ServerStatus, error)
+}
+
+// 未配置 providers 时的默认顺序：地图等公共数据优先使用网关
+var defaultProviderNames = []string{"gateway", "official"}
+
+// 未配置 providers 时玩家数据的默认顺序：优先使用提供全部传奇数据的官方 API，失败或未填写 apitoken 时回退到网关
+var defaultPlayerProviderNames = []string{"official", "gateway"}
+
+// newProvider 按名称创建数据来源
+func newProvider(name string) (StatsProvider, bool) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "gateway":
+		return gatewayProvider{}, true
+	case "official":
+		return officialProvider{}, true
+	default:
+		return nil, false
+	}
+}
+
+// GetStatsProvider 获取按配置顺序依次尝试的数据来源链
+func GetStatsProvider() StatsProvider {
+	return buildProviderChain(defaultProviderNames)
+}
+
+// getPlayerProvider 获取玩家数据的数据来源链，配置了 providers 时与 GetStatsProvider 相同
+func getPlayerProvider() StatsProvider {
+	return buildProviderChain(defaultPlayerProviderNames)
+}
+
+// buildProviderChain 按配置的 providers 创建数据来源链，未配置时使用 defaults
+func buildProviderChain(defaults []string) StatsProvider {
+	names := GetAPIConfig().Providers
+	if len(names) == 0 {
+		names = defaults
+	}
+	var providers []StatsProvider
+	for _, name := range names {
+		p, ok := newProvider(name)
+		if !ok {
+			botlog.Warnf("未知的数据来源: %s", name)
+			continue
+		}
+		providers = append(providers, p)
+	}
+	if len(providers) == 0 {
+		providers = []StatsProvider{gatewayProvider{}, officialProvider{}}
+	}
+	return providerChain(providers)
+}
+
+// providerChain 依次尝试各数据来源，直到有一个成功
+type providerChain []StatsProvider
+
+func (c providerChain) Name() string {
+	names := make([]string, 0, len(c))
+	for _, p := range c {
+		names = append(names, p.Name())
+	}
+	return strings.Join(names, ",")
+}
+
+func (c providerChain) Player(ctx context.Context, EAID string) (*PlayerResponse, error) {
+	ctx = withEndpoint(ctx, endpointPlayer)
+	return tryProviders(c, "玩家数据", func(p StatsProvider) (*PlayerResponse, error) { return p.Player(ctx, EAID) })
+}
+
+func (c providerChain) MapRotation(ctx context.Context) (MapRotate, error) {
+	ctx = withEndpoint(ctx, endpointMap)
+	return tryProviders(c, "地图轮换", func(p StatsProvider) (MapRotate, error) { return p.MapRotation(ctx) })
+}
+
+func (c providerChain) Crafting(ctx context.Context) (Crafting, error) {
+	ctx = withEndpoint(ctx, endpointCrafting)
+	return tryProviders(c, "制造器轮换", func(p StatsProvider) (Crafting, error) { return p.Crafting(ctx) })
+}
+
+func (c providerChain) Predator(ctx context.Context) (Predator, error) {
+	ctx = withEndpoint(ctx, endpointPredator)
+	return tryProviders(c, "猎杀线", func(p StatsProvider) (Predator, error) { return p.Predator(ctx) })
+}
+
+func (c providerChain) ServerStatus(ctx context.Context) (*ServerStatus, error) {
+	ctx = withEndpoint(ctx, endpointStatus)
+	return tryProviders(c, "服务器状态", func(p StatsProvider) (*ServerStatus, error) { return p.ServerStatus(ctx) })
+}
+
+// tryProviders 按顺序调用 fetch，返回第一个成功的结果；玩家不存在属于确定的结果，不再尝试其他来源。
+// 未填写 apitoken 的来源视为不可用，直接跳过。
+// 全部失败时返回合并后的错误，可用 errors.Is 判断其中任一来源的错误（如 ErrCircuitOpen）
+func tryProviders[T any](providers []StatsProvider, what string, fetch func(StatsProvider) (T, error)) (T, error) {
+	var zero T
+	var errs []error
+	var skipped error
+	for _, p := range providers {
+		result, err := fetch(p)
+		if err == nil {
+			return result, nil
+		}
+		if errors.Is(err, ErrNotSupported) {
+			continue
+		}
+		if errors.Is(err, ErrEmptyAPIToken) {
+			skipped = err
+			continue
+		}
+		if errors.Is(err, ErrNoPlayerFound) {
+			return zero, err
+		}
+		botlog.Warnf("从 %s 获取%s失败: %v", p.Name(), what, err)
+		errs = append(errs, err)
+	}
+	switch len(errs) {
+	case 0:
+		if skipped != nil {
+			return zero, skipped
+		}
+		return zero, fmt.Errorf("%w: %s", ErrNotSupported, what)
+	case 1:
+		return zero, errs[0]
+	default:
+		return zero, errors.Join(errs...)
+	}
+}
+
+// fetchBody 发送 GET 请求并读取响应内容，最多读取 limit 字节
+func fetchBody(ctx context.Context, urlStr string, limit int64) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", urlStr, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRequestCreateFailed, err)
+	}
+	return doFetch(req, limit)
+}
+
+// doFetch 发送请求并读取响应内容，最多读取 limit 字节
+func doFetch(req *http.Request, limit int64) ([]byte, error) {
+	resp, err := GetHTTPClient(15 * time.Second).Do(req)
+	if err != nil {
+		return nil, wrapRequestError(err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit))
+	if err != nil {
+		return nil, ErrReadResponseFailed
+	}
+	if strings.Contains(string(body), "API key doesn't exist !") {
+		return nil, ErrWrongAPIToken
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, StatusError(resp.StatusCode)
+	}
+	return body, nil
+}
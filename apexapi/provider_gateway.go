@@ -0,0 +1,90 @@
+package apexapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// apexlegendsstatus 网站使用的网关
+const gatewayURL = "https://lil2-gateway.apexlegendsstatus.com/gateway.php"
+
+// gatewayProvider apexlegendsstatus 网关，无需 API Token；玩家数据只包含当前选择的传奇
+type gatewayProvider struct{}
+
+func (gatewayProvider) Name() string { return "gateway" }
+
+func (gatewayProvider) Player(ctx context.Context, EAID string) (*PlayerResponse, error) {
+	params := url.Values{}
+	params.Add("userName", EAID)
+	params.Add("userPlatform", "PC")
+	params.Add("qt", "stats-single-legend")
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, gatewayURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRequestCreateFailed, err)
+	}
+	// 查询不会修改数据，标记为幂等以便失败时自动重试
+	req.Header["Idempotency-Key"] = nil
+	body, err := doFetch(req, 64<<10)
+	if err != nil {
+		return nil, playerLookupError(err)
+	}
+	return parseGatewayPlayer(body)
+}
+
+// parseGatewayPlayer 解析网关返回的玩家数据，数据位于 statsAPI 中
+func parseGatewayPlayer(body []byte) (*PlayerResponse, error) {
+	var raw struct {
+		StatsAPI json.RawMessage `json:"statsAPI"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidJSON, err)
+	}
+	if len(raw.StatsAPI) == 0 || string(raw.StatsAPI) == "null" {
+		return nil, fmt.Errorf("%w: statsAPI 为空", ErrInvalidJSON)
+	}
+	return parsePlayerResponse(raw.StatsAPI)
+}
+
+func (gatewayProvider) MapRotation(ctx context.Context) (MapRotate, error) {
+	body, err := fetchBody(ctx, gatewayURL+"?qt=map", 16<<10)
+	if err != nil {
+		return nil, err
+	}
+	var raw struct {
+		MapRotate MapRotate `json:"rotation"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidJSON, err)
+	}
+	if len(raw.MapRotate) == 0 {
+		return nil, fmt.Errorf("%w: 地图轮换数据为空", ErrInvalidJSON)
+	}
+	return raw.MapRotate, nil
+}
+
+func (gatewayProvider) Crafting(ctx context.Context) (Crafting, error) {
+	body, err := fetchBody(ctx, gatewayURL+"?qt=crafting", 64<<10)
+	if err != nil {
+		return nil, err
+	}
+	return parseCrafting(body)
+}
+
+func (gatewayProvider) Predator(ctx context.Context) (Predator, error) {
+	body, err := fetchBody(ctx, gatewayURL+"?qt=predator", 16<<10)
+	if err != nil {
+		return nil, err
+	}
+	return parsePredator(body)
+}
+
+func (gatewayProvider) ServerStatus(ctx context.Context) (*ServerStatus, error) {
+	body, err := fetchBody(ctx, gatewayURL+"?qt=servers", 64<<10)
+	if err != nil {
+		return nil, err
+	}
+	return parseServerStatus(body)
+}
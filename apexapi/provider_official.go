@@ -0,0 +1,107 @@
+package apexapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// apexlegendsapi.com 官方 REST API
+const officialAPIURL = "https://api.mozambiquehe.re"
+
+// officialProvider apexlegendsapi.com 官方 API，需要在配置中填写 apitoken
+type officialProvider struct{}
+
+func (officialProvider) Name() string { return "official" }
+
+// get 请求官方 API，API Token 放在请求头中，不会出现在 URL 与错误信息里
+func (officialProvider) get(ctx context.Context, path string, params url.Values, limit int64) ([]byte, error) {
+	token := GetAPIConfig().ApiToken
+	if token == "" {
+		return nil, ErrEmptyAPIToken
+	}
+	urlStr := officialAPIURL + path
+	if len(params) > 0 {
+		urlStr += "?" + params.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, urlStr, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRequestCreateFailed, err)
+	}
+	req.Header.Set("Authorization", token)
+	return doFetch(req, limit)
+}
+
+func (o officialProvider) Player(ctx context.Context, EAID string) (*PlayerResponse, error) {
+	// 完整数据包含全部传奇，最大 1M
+	body, err := o.get(ctx, "/bridge", url.Values{"player": {EAID}, "platform": {"PC"}}, 1<<20)
+	if err != nil {
+		return nil, playerLookupError(err)
+	}
+	return parsePlayerResponse(body)
+}
+
+func (o officialProvider) MapRotation(ctx context.Context) (MapRotate, error) {
+	body, err := o.get(ctx, "/maprotation", url.Values{"version": {"2"}}, 16<<10)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkAPIError(body); err != nil {
+		return nil, err
+	}
+	// version=2 直接以模式代码为键返回各模式的轮换
+	var mapRotate MapRotate
+	if err := json.Unmarshal(body, &mapRotate); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidJSON, err)
+	}
+	if len(mapRotate) == 0 {
+		return nil, fmt.Errorf("%w: 地图轮换数据为空", ErrInvalidJSON)
+	}
+	return mapRotate, nil
+}
+
+func (o officialProvider) Crafting(ctx context.Context) (Crafting, error) {
+	body, err := o.get(ctx, "/crafting", nil, 64<<10)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkAPIError(body); err != nil {
+		return nil, err
+	}
+	return parseCrafting(body)
+}
+
+func (o officialProvider) Predator(ctx context.Context) (Predator, error) {
+	body, err := o.get(ctx, "/predator", nil, 16<<10)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkAPIError(body); err != nil {
+		return nil, err
+	}
+	return parsePredator(body)
+}
+
+func (o officialProvider) ServerStatus(ctx context.Context) (*ServerStatus, error) {
+	body, err := o.get(ctx, "/servers", nil, 64<<10)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkAPIError(body); err != nil {
+		return nil, err
+	}
+	return parseServerStatus(body)
+}
+
+// checkAPIError 官方 API 在状态码 200 时也可能通过 Error 字段返回错误
+func checkAPIError(body []byte) error {
+	var res struct {
+		Error string `json:"Error"`
+	}
+	if err := json.Unmarshal(body, &res); err == nil && res.Error != "" {
+		return UpstreamError(res.Error)
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package apexapi
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// fakeProvider 测试用数据来源，只实现猎杀线与玩家数据
+type fakeProvider struct {
+	name     string
+	predator Predator
+	err      error
+	calls    *int
+}
+
+func (f fakeProvider) Name() string { return f.name }
+
+func (f fakeProvider) Player(ctx context.Context, EAID string) (*PlayerResponse, error) {
+	*f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return nil, ErrNotSupported
+}
+
+func (f fakeProvider) MapRotation(ctx context.Context) (MapRotate, error) {
+	return nil, ErrNotSupported
+}
+
+func (f fakeProvider) Crafting(ctx context.Context) (Crafting, error) {
+	return nil, ErrNotSupported
+}
+
+func (f fakeProvider) ServerStatus(ctx context.Context) (*ServerStatus, error) {
+	return nil, ErrNotSupported
+}
+
+func (f fakeProvider) Predator(ctx context.Context) (Predator, error) {
+	*f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.predator, nil
+}
+
+func TestProviderChainFallback(t *testing.T) {
+	var calls int
+	chain := providerChain{
+		fakeProvider{name: "down", err: StatusError(503), calls: &calls},
+		fakeProvider{name: "up", predator: Predator{"PC": {Val: 15000}}, calls: &calls},
+	}
+
+	predator, err := chain.Predator(context.Background())
+	if err != nil {
+		t.Fatalf("Predator: %v", err)
+	}
+	if predator["PC"].Val != 15000 || calls != 2 {
+		t.Errorf("predator = %+v, calls = %d", predator, calls)
+	}
+	if got := chain.Name(); got != "down,up" {
+		t.Errorf("Name = %q", got)
+	}
+
+	// 非玩家接口的 404 不是确定的结果，应继续尝试下一个来源
+	calls = 0
+	chain[0] = fakeProvider{name: "missing", err: StatusError(404), calls: &calls}
+	if _, err := chain.Predator(context.Background()); err != nil || calls != 2 {
+		t.Errorf("404 后应尝试下一个来源: err = %v, calls = %d", err, calls)
+	}
+}
+
+func TestProviderChainErrors(t *testing.T) {
+	var calls int
+	failing := providerChain{
+		fakeProvider{name: "a", err: ErrRequestFailed, calls: &calls},
+		fakeProvider{name: "b", err: StatusError(503), calls: &calls},
+	}
+	_, err := failing.Predator(context.Background())
+	if !errors.Is(err, ErrRequestFailed) || !errors.Is(err, ErrServiceUnavailable) {
+		t.Errorf("应包含所有来源的错误，实际为 %v", err)
+	}
+	if got := ErrorKindOf(err); got != KindNetwork {
+		t.Errorf("错误类别应取第一个来源，实际为 %v", got)
+	}
+
+	// 所有来源都不支持时返回 ErrNotSupported
+	if _, err := failing.MapRotation(context.Background()); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("MapRotation err = %v, want ErrNotSupported", err)
+	}
+
+	// 玩家不存在是确定的结果，不再尝试后续来源
+	calls = 0
+	notFound := providerChain{
+		fakeProvider{name: "a", err: ErrNoPlayerFound, calls: &calls},
+		fakeProvider{name: "b", err: ErrRequestFailed, calls: &calls},
+	}
+	if _, err := notFound.Player(context.Background(), "x"); !errors.Is(err, ErrNoPlayerFound) || calls != 1 {
+		t.Errorf("Player err = %v, calls = %d", err, calls)
+	}
+}
+
+func TestGetStatsProviderDefault(t *testing.T) {
+	configMx.Lock()
+	saved := ApiConf.Providers
+	ApiConf.Providers = nil
+	configMx.Unlock()
+	t.Cleanup(func() {
+		configMx.Lock()
+		ApiConf.Providers = saved
+		configMx.Unlock()
+	})
+
+	chain, ok := GetStatsProvider().(providerChain)
+	if !ok || chain.Name() != "gateway,official" {
+		t.Errorf("默认数据来源 = %v", GetStatsProvider().Name())
+	}
+	if got := getPlayerProvider().Name(); got != "official,gateway" {
+		t.Errorf("玩家数据默认来源 = %v", got)
+	}
+}
+
+func TestProviderChainSkipsUnconfigured(t *testing.T) {
+	var calls int
+	want := &PlayerResponse{Global: GlobalInfo{Name: "Tester"}}
+	chain := providerChain{
+		fakeProvider{name: "official", err: ErrEmptyAPIToken, calls: &calls},
+		playerProvider{fakeProvider{name: "gateway", calls: &calls}, want},
+	}
+	got, err := chain.Player(context.Background(), "Tester")
+	if err != nil || got != want {
+		t.Fatalf("Player = %v, %v", got, err)
+	}
+
+	// 只有未配置的来源时返回对应的错误
+	if _, err := chain[:1].Player(context.Background(), "Tester"); !errors.Is(err, ErrEmptyAPIToken) {
+		t.Errorf("err = %v, want ErrEmptyAPIToken", err)
+	}
+}
+
+// playerProvider 返回固定玩家数据的测试来源
+type playerProvider struct {
+	fakeProvider
+	player *PlayerResponse
+}
+
+func (p playerProvider) Player(ctx context.Context, EAID string) (*PlayerResponse, error) {
+	return p.player, nil
+}
+
+func TestParseGatewayPlayer(t *testing.T) {
+	player, err := parseGatewayPlayer([]byte(`{"statsAPI": {
+		"global": {"name": "Tester", "uid": 1, "platform": "PC", "level": 100,
+			"rank": {"rankName": "Gold", "rankDiv": 2, "rankScore": 5500}},
+		"legends": {"selected": {"LegendName": "Wraith", "data": [{"name": "BR Kills", "value": 321}]}}
+	}}`))
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	if player.Global.Name != "Tester" || player.Legends.Selected.LegendName != "Wraith" {
+		t.Errorf("玩家数据错误: %+v", player)
+	}
+
+	if _, err := parseGatewayPlayer([]byte(`{"statsAPI": {"Error": "Player not found"}}`)); !errors.Is(err, ErrNoPlayerFound) {
+		t.Errorf("err = %v, want ErrNoPlayerFound", err)
+	}
+	if _, err := parseGatewayPlayer([]byte(`{}`)); !errors.Is(err, ErrInvalidJSON) {
+		t.Errorf("err = %v, want ErrInvalidJSON", err)
+	}
+}
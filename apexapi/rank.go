@@ -0,0 +1,221 @@
+package apexapi
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	botlog "github.com/tencent-connect/botgo/log"
+	"gopkg.in/yaml.v3"
+)
+
+// 进度条长度（格数）
+const rankProgressBarWidth = 10
+
+// 小段的罗马数字表示，下标为 RankDiv
+var rankDivNames = []string{"", "I", "II", "III", "IV"}
+
+// RankTier 一个大段位及其小段分数线
+type RankTier struct {
+	Tier      string `yaml:"tier"`      // 接口中的段位名，如 Diamond
+	Name      string `yaml:"name"`      // 中文名
+	Divisions []int  `yaml:"divisions"` // 各小段起始分数，从 IV 到 I
+}
+
+// RankLadder 按分数从低到高排列的段位表
+type RankLadder []RankTier
+
+// RankStep 段位表中的一个小段
+type RankStep struct {
+	Tier  string
+	Name  string
+	Div   int // 4 到 1，无小段时为 0
+	Score int // 起始分数
+}
+
+// String 小段的中文名，如“钻石 IV”
+func (s RankStep) String() string {
+	if s.Div <= 0 || s.Div >= len(rankDivNames) {
+		return s.Name
+	}
+	return fmt.Sprintf("%s %s", s.Name, rankDivNames[s.Div])
+}
+
+// Steps 将段位表展开为按分数排序的小段列表
+func (l RankLadder) Steps() []RankStep {
+	var steps []RankStep
+	for _, tier := range l {
+		for i, score := range tier.Divisions {
+			div := 0
+			if len(tier.Divisions) > 1 {
+				div = len(tier.Divisions) - i
+			}
+			steps = append(steps, RankStep{Tier: tier.Tier, Name: tier.Name, Div: div, Score: score})
+		}
+	}
+	sort.SliceStable(steps, func(i, j int) bool { return steps[i].Score < steps[j].Score })
+	return steps
+}
+
+// RankProgress 分数在段位表中的位置
+type RankProgress struct {
+	Current RankStep
+	Next    *RankStep // 已是最高段位时为 nil
+}
+
+// Remaining 距下一小段还差的分数
+func (p RankProgress) Remaining(score int) int {
+	if p.Next == nil {
+		return 0
+	}
+	return max(p.Next.Score-score, 0)
+}
+
+// Ratio 当前小段内的进度（0~1）
+func (p RankProgress) Ratio(score int) float64 {
+	if p.Next == nil {
+		return 1
+	}
+	span := p.Next.Score - p.Current.Score
+	if span <= 0 {
+		return 1
+	}
+	return min(max(float64(score-p.Current.Score)/float64(span), 0), 1)
+}
+
+// Progress 获取分数所处的小段，段位表为空时 ok 为 false
+func (l RankLadder) Progress(score int) (RankProgress, bool) {
+	steps := l.Steps()
+	if len(steps) == 0 {
+		return RankProgress{}, false
+	}
+	idx := sort.Search(len(steps), func(i int) bool { return steps[i].Score > score }) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	progress := RankProgress{Current: steps[idx]}
+	if idx+1 < len(steps) {
+		next := steps[idx+1]
+		progress.Next = &next
+	}
+	return progress, true
+}
+
+// rankLadderFile asset/rank_ladder.yaml 的结构
+type rankLadderFile struct {
+	Default RankLadder         `yaml:"default"`
+	Seasons map[int]RankLadder `yaml:"seasons"`
+}
+
+var (
+	rankLadderLock    sync.Mutex
+	rankLadderData    *rankLadderFile
+	rankLadderModTime time.Time
+)
+
+// loadRankLadderFile 读取段位分数线文件，文件修改后自动重新加载
+func loadRankLadderFile() (*rankLadderFile, error) {
+	assetDir, err := GetAssetPath()
+	if err != nil {
+		return nil, fmt.Errorf("获取资源目录失败: %w", err)
+	}
+	path := filepath.Join(assetDir, "rank_ladder.yaml")
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取段位分数线失败: %w", err)
+	}
+
+	rankLadderLock.Lock()
+	defer rankLadderLock.Unlock()
+
+	if rankLadderData != nil && info.ModTime().Equal(rankLadderModTime) {
+		return rankLadderData, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取段位分数线失败: %w", err)
+	}
+	var f rankLadderFile
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("解析段位分数线失败: %w", err)
+	}
+
+	rankLadderData = &f
+	rankLadderModTime = info.ModTime()
+	return rankLadderData, nil
+}
+
+// GetRankLadder 获取当前赛季的段位分数线，未单独配置时使用默认值
+func GetRankLadder() (RankLadder, error) {
+	f, err := loadRankLadderFile()
+	if err != nil {
+		return nil, err
+	}
+	if status, ok, _ := GetSeasonStatus(); ok {
+		if ladder, ok := f.Seasons[status.Season.Number]; ok && len(ladder) > 0 {
+			return ladder, nil
+		}
+	}
+	return f.Default, nil
+}
+
+// GetRankTierName 获取段位中文名，未配置时返回原名
+func GetRankTierName(tier string) string {
+	ladder, err := GetRankLadder()
+	if err != nil {
+		return tier
+	}
+	for _, t := range ladder {
+		if strings.EqualFold(t.Tier, tier) {
+			return t.Name
+		}
+	}
+	return tier
+}
+
+// ProgressBar 文本进度条，如“[■■■□□□□□□□] 30%”
+func ProgressBar(ratio float64) string {
+	ratio = min(max(ratio, 0), 1)
+	filled := int(ratio*rankProgressBarWidth + 0.5)
+	return fmt.Sprintf("[%s%s] %d%%", strings.Repeat("■", filled), strings.Repeat("□", rankProgressBarWidth-filled), int(ratio*100))
+}
+
+// FormatRankProgress 格式化距下一小段的分数与进度条
+func FormatRankProgress(score int) string {
+	ladder, err := GetRankLadder()
+	if err != nil {
+		botlog.Warnf("获取段位分数线失败: %v", err)
+		return ""
+	}
+	progress, ok := ladder.Progress(score)
+	if !ok {
+		return ""
+	}
+	if progress.Next == nil {
+		return fmt.Sprintf("已达到 %s 分数线\n", progress.Current)
+	}
+	return fmt.Sprintf("距离 %s 还差 %d 分\n段位进度: %s\n", progress.Next, progress.Remaining(score), ProgressBar(progress.Ratio(score)))
+}
+
+// FormatRankChange 比较两次分数所处的小段，返回晋级/掉段提示；未变化时返回空字符串
+func FormatRankChange(lastScore, score int) string {
+	ladder, err := GetRankLadder()
+	if err != nil {
+		return ""
+	}
+	last, ok1 := ladder.Progress(lastScore)
+	cur, ok2 := ladder.Progress(score)
+	if !ok1 || !ok2 || last.Current.Score == cur.Current.Score {
+		return ""
+	}
+	if score > lastScore {
+		return fmt.Sprintf("🎉 恭喜晋级 %s！（上次为 %s）\n", cur.Current, last.Current)
+	}
+	return fmt.Sprintf("段位下降至 %s（上次为 %s），加油！\n", cur.Current, last.Current)
+}
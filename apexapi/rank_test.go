@@ -0,0 +1,49 @@
+package apexapi
+
+import "testing"
+
+func TestRankLadderProgress(t *testing.T) {
+	ladder := RankLadder{
+		{Tier: "Platinum", Name: "白金", Divisions: []int{8000, 8750, 9500, 10250}},
+		{Tier: "Diamond", Name: "钻石", Divisions: []int{11000, 12000, 13000, 14000}},
+		{Tier: "Master", Name: "大师", Divisions: []int{15000}},
+	}
+
+	tests := []struct {
+		score     int
+		current   string
+		next      string
+		remaining int
+	}{
+		{score: 10800, current: "白金 I", next: "钻石 IV", remaining: 200},
+		{score: 11000, current: "钻石 IV", next: "钻石 III", remaining: 1000},
+		{score: 14999, current: "钻石 I", next: "大师", remaining: 1},
+		{score: 20000, current: "大师", next: ""},
+		{score: 100, current: "白金 IV", next: "白金 III", remaining: 8650},
+	}
+	for _, tt := range tests {
+		progress, ok := ladder.Progress(tt.score)
+		if !ok {
+			t.Fatalf("分数 %d 未找到段位", tt.score)
+		}
+		if got := progress.Current.String(); got != tt.current {
+			t.Errorf("分数 %d 当前段位为 %s，期望 %s", tt.score, got, tt.current)
+		}
+		if tt.next == "" {
+			if progress.Next != nil {
+				t.Errorf("分数 %d 不应有下一段位，得到 %s", tt.score, progress.Next)
+			}
+			continue
+		}
+		if progress.Next == nil || progress.Next.String() != tt.next {
+			t.Errorf("分数 %d 下一段位为 %v，期望 %s", tt.score, progress.Next, tt.next)
+		}
+		if got := progress.Remaining(tt.score); got != tt.remaining {
+			t.Errorf("分数 %d 距下一段位 %d 分，期望 %d", tt.score, got, tt.remaining)
+		}
+	}
+
+	if got := ProgressBar(0.3); got != "[■■■□□□□□□□] 30%" {
+		t.Errorf("进度条错误: %s", got)
+	}
+}
@@ -0,0 +1,182 @@
+package apexapi
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	botlog "github.com/tencent-connect/botgo/log"
+)
+
+const (
+	// 轮询间隔的下限，避免过于频繁地请求接口
+	rankPollMinInterval = 5 * time.Minute
+	// 轮询时相邻两次查询之间的间隔
+	rankPollRequestGap = 3 * time.Second
+	// 分数变化超过该值时即使未跨越段位也会播报
+	RankSwingThreshold = 300
+	// 段位分数历史的保留时长
+	rankHistoryRetention = 180 * 24 * time.Hour
+)
+
+// RankHistoryData 段位分数历史，与玩家绑定数据共用同一个数据库
+type RankHistoryData struct {
+	players *PlayerData
+}
+
+var RankHistory = RankHistoryData{players: &Players}
+
+// Record 记录一次段位分数
+func (h RankHistoryData) Record(qqID string, score int, at time.Time) error {
+	p := h.players
+	p.Lock.Lock()
+	defer p.Lock.Unlock()
+
+	if err := p.ensureInit(); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := p.db.ExecContext(ctx, `
+		INSERT INTO rank_history (qq_id, rank_score, recorded_at) VALUES (?, ?, ?)
+	`, qqID, score, at.Unix()); err != nil {
+		return fmt.Errorf("保存段位历史失败: %w", err)
+	}
+	if _, err := p.db.ExecContext(ctx, "DELETE FROM rank_history WHERE recorded_at < ?",
+		time.Now().Add(-rankHistoryRetention).Unix()); err != nil {
+		return fmt.Errorf("清理段位历史失败: %w", err)
+	}
+	return nil
+}
+
+// RankChange 轮询发现的一次需要播报的段位变化
+type RankChange struct {
+	QQ        string
+	EAID      string
+	LastScore int
+	Score     int
+	Notice    string   // 跨越段位时的晋级/掉段提示，仅分数大幅变化时为空
+	Groups    []string // 开启了播报且该成员使用过机器人的群
+}
+
+// Delta 分数变化
+func (c RankChange) Delta() int {
+	return c.Score - c.LastScore
+}
+
+// GetPollInterval 获取配置的轮询间隔，未配置或配置无效时 ok 为 false
+func GetPollInterval() (time.Duration, bool) {
+	value := GetAPIConfig().PollInterval
+	if value == "" {
+		return 0, false
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval <= 0 {
+		botlog.Warnf("poll_interval 配置无效: %q", value)
+		return 0, false
+	}
+	return max(interval, rankPollMinInterval), true
+}
+
+// StartRankPoller 按配置的间隔在后台轮询所有已绑定玩家的段位分数，需要播报时调用 notify；未配置间隔时不启动
+func StartRankPoller(ctx context.Context, notify func(context.Context, RankChange)) bool {
+	interval, ok := GetPollInterval()
+	if !ok {
+		return false
+	}
+	go func() {
+		for {
+			pollBindings(ctx, notify)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(interval):
+			}
+		}
+	}()
+	return true
+}
+
+// pollBindings 依次查询所有已绑定玩家，更新分数并记录历史
+func pollBindings(ctx context.Context, notify func(context.Context, RankChange)) {
+	bindings, err := Players.GetAll()
+	if err != nil {
+		botlog.Warnf("轮询段位时读取绑定数据失败: %v", err)
+		return
+	}
+
+	for i, binding := range bindings {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(rankPollRequestGap):
+			}
+		}
+
+		change, ok, err := pollBinding(ctx, binding)
+		if err != nil {
+			botlog.Warnf("轮询 %s 的段位失败: %v", binding.EAID, err)
+			continue
+		}
+		if ok && len(change.Groups) > 0 {
+			notify(ctx, change)
+		}
+	}
+}
+
+// pollBinding 查询单个玩家的段位分数，分数变化时更新绑定数据；ok 表示需要播报
+func pollBinding(ctx context.Context, binding PlayerBindingData) (RankChange, bool, error) {
+	player, err := GetPlayerData(ctx, binding.EAID)
+	if err != nil {
+		return RankChange{}, false, err
+	}
+	score, err := GetPlayerRank(player)
+	if err != nil || score <= 0 || score == binding.LastRankScore {
+		return RankChange{}, false, err
+	}
+
+	change := RankChange{
+		QQ:        binding.QQ,
+		EAID:      binding.EAID,
+		LastScore: binding.LastRankScore,
+		Score:     score,
+		Notice:    FormatRankChange(binding.LastRankScore, score),
+	}
+
+	now := time.Now()
+	binding.LastRankScore = score
+	binding.LastUpdateTime = now
+	Players.Set(binding.QQ, binding)
+	if err := RankHistory.Record(binding.QQ, score, now); err != nil {
+		botlog.Warnf("%v", err)
+	}
+
+	// 首次记录分数时不播报
+	if change.LastScore <= 0 {
+		return change, false, nil
+	}
+	if change.Notice == "" && abs(change.Delta()) < RankSwingThreshold {
+		return change, false, nil
+	}
+
+	groups, err := GroupSettings.MemberGroups(binding.QQ)
+	if err != nil {
+		return change, false, err
+	}
+	for _, groupID := range groups {
+		if GroupSettings.RankAnnounceEnabled(groupID) {
+			change.Groups = append(change.Groups, groupID)
+		}
+	}
+	return change, true, nil
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
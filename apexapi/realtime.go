@@ -0,0 +1,210 @@
+package apexapi
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// 群在线状态最多查询的成员数
+	onlineMaxMembers = 20
+	// 查询群在线状态时的并发数，避免触发接口速率限制
+	onlineQueryConcurrency = 2
+)
+
+// 接口中的 currentState 取值
+const (
+	RealtimeOffline = "offline"
+	RealtimeInLobby = "inLobby"
+	RealtimeInMatch = "inMatch"
+)
+
+// RealtimeInfo 玩家实时状态
+type RealtimeInfo struct {
+	LobbyState         string `json:"lobbyState"` // open / invite / closed
+	IsOnline           int    `json:"isOnline"`
+	IsInGame           int    `json:"isInGame"`
+	CanJoin            int    `json:"canJoin"`
+	PartyFull          int    `json:"partyFull"`
+	SelectedLegend     string `json:"selectedLegend"`
+	CurrentState       string `json:"currentState"`
+	CurrentStateSince  int64  `json:"currentStateSinceTimestamp"` // 无数据时为 -1
+	CurrentStateAsText string `json:"currentStateAsText"`
+}
+
+// Online 是否在线
+func (r RealtimeInfo) Online() bool {
+	return r.IsOnline == 1 || r.CurrentState == RealtimeInLobby || r.CurrentState == RealtimeInMatch
+}
+
+// InMatch 是否在对局中
+func (r RealtimeInfo) InMatch() bool {
+	return r.IsInGame == 1 || r.CurrentState == RealtimeInMatch
+}
+
+// StateDuration 当前状态已持续的时间，无数据时 ok 为 false
+func (r RealtimeInfo) StateDuration() (time.Duration, bool) {
+	if r.CurrentStateSince <= 0 {
+		return 0, false
+	}
+	return max(time.Since(time.Unix(r.CurrentStateSince, 0)), 0), true
+}
+
+// StateText 当前状态的中文描述
+func (r RealtimeInfo) StateText() string {
+	switch {
+	case !r.Online():
+		return "离线"
+	case r.InMatch():
+		return "对局中"
+	default:
+		return "大厅中"
+	}
+}
+
+// lobbyStateText 队伍状态的中文描述
+func (r RealtimeInfo) lobbyStateText() string {
+	if r.PartyFull == 1 {
+		return "队伍已满"
+	}
+	switch r.LobbyState {
+	case "open":
+		return "队伍开放"
+	case "invite":
+		return "仅限邀请"
+	case "closed":
+		return "队伍关闭"
+	default:
+		return ""
+	}
+}
+
+// FormatRealtimeStatus 格式化玩家的在线状态
+func FormatRealtimeStatus(player *PlayerResponse) string {
+	if player == nil {
+		return "玩家数据为空"
+	}
+	r := player.Realtime
+
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("%s 当前%s", player.Global.Name, r.StateText()))
+	if d, ok := r.StateDuration(); ok && r.Online() {
+		output.WriteString(fmt.Sprintf("（已持续 %s）", FormatDuration(d)))
+	}
+	output.WriteString("\n")
+	if !r.Online() {
+		return output.String()
+	}
+
+	if r.SelectedLegend != "" {
+		output.WriteString(fmt.Sprintf("当前传奇: %s\n", GetLegendName(r.SelectedLegend)))
+	}
+	if lobby := r.lobbyStateText(); lobby != "" {
+		output.WriteString(fmt.Sprintf("队伍状态: %s", lobby))
+		if r.CanJoin == 1 && r.PartyFull != 1 {
+			output.WriteString("，可加入")
+		}
+		output.WriteString("\n")
+	}
+	return output.String()
+}
+
+// MemberStatus 群成员的在线状态
+type MemberStatus struct {
+	EAID     string
+	Realtime RealtimeInfo
+	Err      error
+}
+
+// GetGroupOnlineStatus 查询在群内绑定过账号的成员的在线状态（按最近使用排序，最多 onlineMaxMembers 人）
+func GetGroupOnlineStatus(ctx context.Context, groupID string) ([]MemberStatus, error) {
+	members, err := GroupSettings.GroupMembers(groupID)
+	if err != nil {
+		return nil, fmt.Errorf("读取群成员失败: %w", err)
+	}
+
+	var eaids []string
+	seen := make(map[string]bool)
+	for _, qqID := range members {
+		binding, ok := Players.Get(qqID)
+		if !ok || seen[strings.ToLower(binding.EAID)] {
+			continue
+		}
+		seen[strings.ToLower(binding.EAID)] = true
+		eaids = append(eaids, binding.EAID)
+		if len(eaids) >= onlineMaxMembers {
+			break
+		}
+	}
+
+	statuses := make([]MemberStatus, len(eaids))
+	sem := make(chan struct{}, onlineQueryConcurrency)
+	var wg sync.WaitGroup
+	for i, eaid := range eaids {
+		wg.Add(1)
+		go func(i int, eaid string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			statuses[i].EAID = eaid
+			player, err := GetPlayerData(ctx, eaid)
+			if err != nil {
+				statuses[i].Err = err
+				return
+			}
+			statuses[i].Realtime = player.Realtime
+		}(i, eaid)
+	}
+	wg.Wait()
+	return statuses, nil
+}
+
+// FormatGroupOnlineStatus 格式化群成员在线状态，在线的成员排在前面
+func FormatGroupOnlineStatus(statuses []MemberStatus) string {
+	if len(statuses) == 0 {
+		return "本群还没有成员绑定 EAID，使用 /a绑定 <EAID> 进行绑定"
+	}
+
+	sorted := append([]MemberStatus(nil), statuses...)
+	rank := func(s MemberStatus) int {
+		switch {
+		case s.Err != nil:
+			return 3
+		case !s.Realtime.Online():
+			return 2
+		case s.Realtime.InMatch():
+			return 1
+		default:
+			return 0 // 大厅中的成员最适合组队，排在最前
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool { return rank(sorted[i]) < rank(sorted[j]) })
+
+	online := 0
+	var lines strings.Builder
+	for _, s := range sorted {
+		if s.Err != nil {
+			lines.WriteString(fmt.Sprintf("  %s: 查询失败\n", s.EAID))
+			continue
+		}
+		r := s.Realtime
+		if r.Online() {
+			online++
+		}
+		line := fmt.Sprintf("  %s: %s", s.EAID, r.StateText())
+		if r.Online() && r.SelectedLegend != "" {
+			line += fmt.Sprintf("（%s）", GetLegendName(r.SelectedLegend))
+		}
+		if r.Online() && r.PartyFull == 1 {
+			line += " 队伍已满"
+		}
+		lines.WriteString(line + "\n")
+	}
+
+	return fmt.Sprintf("本群已绑定成员在线情况（%d/%d 在线）:\n%s", online, len(statuses), lines.String())
+}
@@ -0,0 +1,49 @@
+package apexapi
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRealtimeStatus(t *testing.T) {
+	player, err := parsePlayerResponse([]byte(`{
+		"global": {"name": "Tester", "uid": 1},
+		"realtime": {"lobbyState": "open", "isOnline": 1, "isInGame": 0, "canJoin": 1, "partyFull": 0,
+			"selectedLegend": "Wraith", "currentState": "inLobby", "currentStateSinceTimestamp": -1}
+	}`))
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	r := player.Realtime
+	if !r.Online() || r.InMatch() || r.StateText() != "大厅中" {
+		t.Errorf("实时状态错误: %+v", r)
+	}
+	if _, ok := r.StateDuration(); ok {
+		t.Error("时间戳为 -1 时不应有持续时间")
+	}
+	if msg := FormatRealtimeStatus(player); !strings.Contains(msg, "可加入") {
+		t.Errorf("缺少队伍状态: %s", msg)
+	}
+}
+
+func TestFormatGroupOnlineStatus(t *testing.T) {
+	msg := FormatGroupOnlineStatus([]MemberStatus{
+		{EAID: "Offline", Realtime: RealtimeInfo{CurrentState: RealtimeOffline}},
+		{EAID: "Broken", Err: errors.New("超时")},
+		{EAID: "Playing", Realtime: RealtimeInfo{IsOnline: 1, IsInGame: 1, CurrentState: RealtimeInMatch}},
+		{EAID: "Lobby", Realtime: RealtimeInfo{IsOnline: 1, CurrentState: RealtimeInLobby}},
+	})
+	if !strings.Contains(msg, "2/4 在线") {
+		t.Errorf("在线人数错误: %s", msg)
+	}
+	order := []string{"Lobby", "Playing", "Offline", "Broken"}
+	last := -1
+	for _, eaid := range order {
+		idx := strings.Index(msg, eaid)
+		if idx < last {
+			t.Fatalf("排序错误，期望 %v: %s", order, msg)
+		}
+		last = idx
+	}
+}
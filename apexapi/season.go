@@ -0,0 +1,243 @@
+package apexapi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	botlog "github.com/tencent-connect/botgo/log"
+	"gopkg.in/yaml.v3"
+)
+
+// 远程赛季日历的缓存时长
+const seasonRemoteCacheDuration = 6 * time.Hour
+
+// 远程赛季日历获取失败后，在该时间内不再重试，直接使用本地数据
+const seasonRemoteRetryInterval = 5 * time.Minute
+
+// SeasonSplit 排位赛季的一个阶段（上/下半赛季）
+type SeasonSplit struct {
+	Name  string    `yaml:"name" json:"name"`
+	Start time.Time `yaml:"start" json:"start"`
+	End   time.Time `yaml:"end" json:"end"`
+}
+
+// Season 赛季信息
+type Season struct {
+	Number int           `yaml:"number" json:"number"`
+	Name   string        `yaml:"name" json:"name"`
+	Start  time.Time     `yaml:"start" json:"start"`
+	End    time.Time     `yaml:"end" json:"end"`
+	Splits []SeasonSplit `yaml:"splits" json:"splits"`
+}
+
+// Title 赛季标题，如“第27赛季 X”
+func (s Season) Title() string {
+	if s.Name == "" {
+		return fmt.Sprintf("第%d赛季", s.Number)
+	}
+	return fmt.Sprintf("第%d赛季 %s", s.Number, s.Name)
+}
+
+// SeasonCalendar 赛季日历
+type SeasonCalendar struct {
+	Seasons []Season `yaml:"seasons" json:"seasons"`
+}
+
+// SeasonStatus 某一时刻所处的赛季与排位阶段
+type SeasonStatus struct {
+	Season    Season
+	Split     *SeasonSplit // 当前排位阶段，不在任何阶段内时为 nil
+	NextSplit *SeasonSplit // 本赛季的下一排位阶段
+}
+
+// SplitRemaining 距当前排位阶段结束的剩余时间
+func (s SeasonStatus) SplitRemaining() time.Duration {
+	if s.Split == nil {
+		return 0
+	}
+	return max(time.Until(s.Split.End), 0)
+}
+
+// SeasonRemaining 距赛季结束的剩余时间
+func (s SeasonStatus) SeasonRemaining() time.Duration {
+	return max(time.Until(s.Season.End), 0)
+}
+
+// StatusAt 获取指定时刻所处的赛季，不在任何赛季内时 ok 为 false
+func (c *SeasonCalendar) StatusAt(t time.Time) (SeasonStatus, bool) {
+	for _, season := range c.Seasons {
+		if t.Before(season.Start) || !t.Before(season.End) {
+			continue
+		}
+		status := SeasonStatus{Season: season}
+		splits := append([]SeasonSplit(nil), season.Splits...)
+		sort.Slice(splits, func(i, j int) bool { return splits[i].Start.Before(splits[j].Start) })
+		for i := range splits {
+			split := splits[i]
+			if !t.Before(split.Start) && t.Before(split.End) {
+				status.Split = &split
+				continue
+			}
+			if split.Start.After(t) && status.NextSplit == nil {
+				status.NextSplit = &split
+			}
+		}
+		return status, true
+	}
+	return SeasonStatus{}, false
+}
+
+var (
+	seasonLock         sync.Mutex
+	localSeason        *SeasonCalendar
+	localSeasonModTime time.Time
+	remoteSeason       *SeasonCalendar
+	remoteSeasonAt     time.Time
+	remoteSeasonErr    error     // 最近一次获取远程日历的错误
+	remoteSeasonErrAt  time.Time // 最近一次获取失败的时间
+)
+
+// GetSeasonCalendar 获取赛季日历：配置了 season_url 时优先使用远程数据，失败时回退到 asset/season.yaml
+func GetSeasonCalendar() (*SeasonCalendar, error) {
+	if url := GetAPIConfig().SeasonURL; url != "" {
+		if cal, err := getRemoteSeasonCalendar(url); err == nil {
+			return cal, nil
+		}
+	}
+	return getLocalSeasonCalendar()
+}
+
+// GetSeasonStatus 获取当前所处的赛季
+func GetSeasonStatus() (SeasonStatus, bool, error) {
+	cal, err := GetSeasonCalendar()
+	if err != nil {
+		return SeasonStatus{}, false, err
+	}
+	status, ok := cal.StatusAt(time.Now())
+	return status, ok, nil
+}
+
+// getLocalSeasonCalendar 读取本地赛季日历，文件修改后自动重新加载
+func getLocalSeasonCalendar() (*SeasonCalendar, error) {
+	assetDir, err := GetAssetPath()
+	if err != nil {
+		return nil, fmt.Errorf("获取资源目录失败: %w", err)
+	}
+	path := filepath.Join(assetDir, "season.yaml")
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取赛季日历失败: %w", err)
+	}
+
+	seasonLock.Lock()
+	defer seasonLock.Unlock()
+
+	if localSeason != nil && info.ModTime().Equal(localSeasonModTime) {
+		return localSeason, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取赛季日历失败: %w", err)
+	}
+	var cal SeasonCalendar
+	if err := yaml.Unmarshal(data, &cal); err != nil {
+		return nil, fmt.Errorf("解析赛季日历失败: %w", err)
+	}
+
+	localSeason = &cal
+	localSeasonModTime = info.ModTime()
+	return localSeason, nil
+}
+
+// getRemoteSeasonCalendar 获取远程赛季日历（带缓存），失败后的一段时间内直接返回上次的错误，避免每次调用都请求远程
+func getRemoteSeasonCalendar(url string) (*SeasonCalendar, error) {
+	seasonLock.Lock()
+	if remoteSeason != nil && time.Since(remoteSeasonAt) < seasonRemoteCacheDuration {
+		cal := remoteSeason
+		seasonLock.Unlock()
+		return cal, nil
+	}
+	if remoteSeasonErr != nil && time.Since(remoteSeasonErrAt) < seasonRemoteRetryInterval {
+		err := remoteSeasonErr
+		seasonLock.Unlock()
+		return nil, err
+	}
+	seasonLock.Unlock()
+
+	cal, err := fetchRemoteSeasonCalendar(url)
+
+	seasonLock.Lock()
+	defer seasonLock.Unlock()
+	if err != nil {
+		botlog.Warnf("获取远程赛季日历失败，%v 内使用本地数据: %v", seasonRemoteRetryInterval, err)
+		remoteSeasonErr = err
+		remoteSeasonErrAt = time.Now()
+		return nil, err
+	}
+	remoteSeason = cal
+	remoteSeasonAt = time.Now()
+	remoteSeasonErr = nil
+	return cal, nil
+}
+
+// fetchRemoteSeasonCalendar 请求远程赛季日历（YAML 或 JSON，结构同 season.yaml）
+func fetchRemoteSeasonCalendar(url string) (*SeasonCalendar, error) {
+	req, err := http.NewRequestWithContext(withEndpoint(context.Background(), endpointSeason), "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRequestCreateFailed, err)
+	}
+	resp, err := GetHTTPClient(10 * time.Second).Do(req)
+	if err != nil {
+		return nil, wrapRequestError(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, StatusError(resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if err != nil {
+		return nil, ErrReadResponseFailed
+	}
+
+	// JSON 是 YAML 的子集，统一按 YAML 解析
+	var cal SeasonCalendar
+	if err := yaml.Unmarshal(body, &cal); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidJSON, err)
+	}
+	if len(cal.Seasons) == 0 {
+		return nil, fmt.Errorf("远程赛季日历为空")
+	}
+	return &cal, nil
+}
+
+// FormatSeasonStatus 格式化赛季信息
+func FormatSeasonStatus(status SeasonStatus) string {
+	const dateLayout = "2006-01-02 15:04"
+	s := status.Season
+
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("当前赛季：%s\n", s.Title()))
+	output.WriteString(fmt.Sprintf("赛季时间：%s ~ %s\n", s.Start.Local().Format(dateLayout), s.End.Local().Format(dateLayout)))
+	if status.Split != nil {
+		output.WriteString(fmt.Sprintf("当前排位：%s（%s ~ %s）\n", status.Split.Name,
+			status.Split.Start.Local().Format(dateLayout), status.Split.End.Local().Format(dateLayout)))
+		output.WriteString(fmt.Sprintf("排位结束倒计时：%s\n", FormatDuration(status.SplitRemaining())))
+	}
+	if status.NextSplit != nil {
+		output.WriteString(fmt.Sprintf("下一阶段：%s（%s 开始）\n", status.NextSplit.Name, status.NextSplit.Start.Local().Format(dateLayout)))
+	}
+	output.WriteString(fmt.Sprintf("赛季结束倒计时：%s", FormatDuration(status.SeasonRemaining())))
+	return output.String()
+}
@@ -0,0 +1,106 @@
+package apexapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestSeasonStatusAt(t *testing.T) {
+	start := time.Date(2026, 8, 4, 17, 0, 0, 0, time.UTC)
+	mid := start.AddDate(0, 0, 49)
+	end := start.AddDate(0, 0, 91)
+	cal := SeasonCalendar{Seasons: []Season{{
+		Number: 30,
+		Start:  start,
+		End:    end,
+		Splits: []SeasonSplit{
+			{Name: "下半赛季", Start: mid, End: end},
+			{Name: "上半赛季", Start: start, End: mid},
+		},
+	}}}
+
+	status, ok := cal.StatusAt(start.Add(time.Hour))
+	if !ok || status.Split == nil || status.Split.Name != "上半赛季" {
+		t.Fatalf("当前排位阶段错误: %+v", status)
+	}
+	if status.NextSplit == nil || status.NextSplit.Name != "下半赛季" {
+		t.Fatalf("下一排位阶段错误: %+v", status.NextSplit)
+	}
+
+	status, ok = cal.StatusAt(mid)
+	if !ok || status.Split == nil || status.Split.Name != "下半赛季" || status.NextSplit != nil {
+		t.Fatalf("切换阶段时刻的排位阶段错误: %+v", status)
+	}
+
+	if _, ok := cal.StatusAt(end); ok {
+		t.Fatal("赛季结束后不应处于赛季内")
+	}
+}
+
+func TestSeasonCalendarAsset(t *testing.T) {
+	assetDir, err := GetAssetPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(assetDir, "season.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cal SeasonCalendar
+	if err := yaml.Unmarshal(data, &cal); err != nil {
+		t.Fatalf("解析赛季日历失败: %v", err)
+	}
+	for _, s := range cal.Seasons {
+		if !s.End.After(s.Start) {
+			t.Fatalf("%s 的结束时间早于开始时间", s.Title())
+		}
+		for _, split := range s.Splits {
+			if split.Start.Before(s.Start) || split.End.After(s.End) {
+				t.Fatalf("%s 的排位阶段 %s 超出赛季范围", s.Title(), split.Name)
+			}
+		}
+	}
+}
+
+func TestRemoteSeasonCalendarFailureCached(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	seasonLock.Lock()
+	remoteSeason, remoteSeasonErr = nil, nil
+	seasonLock.Unlock()
+	defer func() {
+		seasonLock.Lock()
+		remoteSeason, remoteSeasonErr = nil, nil
+		seasonLock.Unlock()
+	}()
+
+	for i := 0; i < 3; i++ {
+		if _, err := getRemoteSeasonCalendar(srv.URL); err == nil {
+			t.Fatal("远程日历不可用时应返回错误")
+		}
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("失败后应暂停重试，请求次数 = %d", got)
+	}
+
+	// 重试间隔过后重新请求
+	seasonLock.Lock()
+	remoteSeasonErrAt = time.Now().Add(-seasonRemoteRetryInterval)
+	seasonLock.Unlock()
+	_, _ = getRemoteSeasonCalendar(srv.URL)
+	if got := calls.Load(); got != 2 {
+		t.Errorf("重试间隔过后应重新请求，请求次数 = %d", got)
+	}
+}
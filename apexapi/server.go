@@ -0,0 +1,174 @@
+package apexapi
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/newton-miku/apexQQbot/tools"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	serverImageWidth   = 1060
+	serverHeaderHeight = 130
+	serverRowHeight    = 52
+	serverFooterHeight = 20
+)
+
+// 区服中文名翻译器（修改 asset/server_dict.json 后自动生效）
+var (
+	serverTranslator *tools.Translator
+	serverDictPath   = "./asset/server_dict.json"
+	serverDictOnce   sync.Once
+)
+
+func getServerTranslator() *tools.Translator {
+	serverDictOnce.Do(func() {
+		trans, err := tools.NewTranslator(serverDictPath)
+		if err != nil {
+			// 静默失败，使用区服代码
+			return
+		}
+		serverTranslator = trans
+	})
+	return serverTranslator
+}
+
+// ServerRegion 区服数据中心
+type ServerRegion struct {
+	Code  string   `yaml:"code"`  // 区服代码，如 ap-east-1
+	Name  string   `yaml:"name"`  // 英文名
+	Hosts []string `yaml:"hosts"` // 测速地址
+}
+
+// ChineseName 区服中文名，未配置时返回区服代码
+func (s ServerRegion) ChineseName() string {
+	if trans := getServerTranslator(); trans != nil {
+		if name, ok := trans.Lookup(s.Code); ok {
+			return name
+		}
+	}
+	return s.Code
+}
+
+// Match 判断关键字是否匹配区服代码、英文名或中文名（忽略大小写）
+func (s ServerRegion) Match(keyword string) bool {
+	keyword = strings.ToLower(strings.TrimSpace(keyword))
+	if keyword == "" {
+		return true
+	}
+	for _, field := range []string{s.Code, s.Name, s.ChineseName()} {
+		if strings.Contains(strings.ToLower(field), keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	serverLock    sync.Mutex
+	serverList    []ServerRegion
+	serverModTime time.Time
+)
+
+// GetServerRegions 读取区服列表，文件修改后自动重新加载
+func GetServerRegions() ([]ServerRegion, error) {
+	assetDir, err := GetAssetPath()
+	if err != nil {
+		return nil, fmt.Errorf("获取资源目录失败: %w", err)
+	}
+	path := filepath.Join(assetDir, "servers.yaml")
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取区服列表失败: %w", err)
+	}
+
+	serverLock.Lock()
+	defer serverLock.Unlock()
+
+	if serverList != nil && info.ModTime().Equal(serverModTime) {
+		return serverList, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取区服列表失败: %w", err)
+	}
+	var f struct {
+		Servers []ServerRegion `yaml:"servers"`
+	}
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("解析区服列表失败: %w", err)
+	}
+
+	serverList = f.Servers
+	serverModTime = info.ModTime()
+	return serverList, nil
+}
+
+// FilterServerRegions 按关键字筛选区服，关键字为空时返回全部
+func FilterServerRegions(servers []ServerRegion, keyword string) []ServerRegion {
+	var result []ServerRegion
+	for _, s := range servers {
+		if s.Match(keyword) {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// RenderServerImage 绘制区服对照表
+func RenderServerImage(servers []ServerRegion) ([]byte, error) {
+	if len(servers) == 0 {
+		return nil, fmt.Errorf("没有可展示的区服")
+	}
+	fontPath, err := getMapFontPath()
+	if err != nil {
+		return nil, err
+	}
+
+	height := serverHeaderHeight + len(servers)*serverRowHeight + serverFooterHeight
+	dst := image.NewRGBA(image.Rect(0, 0, serverImageWidth, height))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(cardBackground), image.Point{}, draw.Src)
+
+	const codeX, zhX, enX, hostX = 30, 330, 510, 700
+	lines := []tools.TextLine{
+		{Text: "数据中心", Size: 40, X: 30, Y: 55, FontPath: fontPath, Color: cardValueColor},
+		{Text: "区服代码", Size: 28, X: codeX, Y: 115, FontPath: fontPath, Color: cardLabelColor},
+		{Text: "区服名称", Size: 28, X: zhX, Y: 115, FontPath: fontPath, Color: cardLabelColor},
+		{Text: "英文名", Size: 28, X: enX, Y: 115, FontPath: fontPath, Color: cardLabelColor},
+		{Text: "测速地址", Size: 28, X: hostX, Y: 115, FontPath: fontPath, Color: cardLabelColor},
+	}
+
+	for i, s := range servers {
+		top := serverHeaderHeight + i*serverRowHeight
+		if i%2 == 0 {
+			draw.Draw(dst, image.Rect(0, top, serverImageWidth, top+serverRowHeight), image.NewUniform(cardStripe), image.Point{}, draw.Src)
+		}
+		host := ""
+		if len(s.Hosts) > 0 {
+			host = s.Hosts[0]
+		}
+		lines = append(lines,
+			tools.TextLine{Text: s.Code, Size: 28, X: codeX, Y: top + 36, FontPath: fontPath, Color: cardValueColor},
+			tools.TextLine{Text: s.ChineseName(), Size: 32, X: zhX, Y: top + 38, FontPath: fontPath, Color: cardHighlightColor},
+			tools.TextLine{Text: s.Name, Size: 24, X: enX, Y: top + 35, FontPath: fontPath, Color: cardValueColor},
+			tools.TextLine{Text: host, Size: 20, X: hostX, Y: top + 34, FontPath: fontPath, Color: color.RGBA{170, 170, 180, 255}},
+		)
+	}
+	tools.AddTextToImageInPlace(dst, lines)
+
+	data, err := encodeJPEG(dst)
+	if err != nil {
+		return nil, fmt.Errorf("编码图片失败: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,47 @@
+package apexapi
+
+import (
+	"bytes"
+	"image/jpeg"
+	"testing"
+)
+
+func TestServerRegions(t *testing.T) {
+	servers, err := GetServerRegions()
+	if err != nil {
+		t.Fatalf("读取区服列表失败: %v", err)
+	}
+	if len(servers) == 0 {
+		t.Fatal("区服列表为空")
+	}
+
+	tests := map[string]string{
+		"ap-east":   "ap-east-1",
+		"TOKYO":     "ap-northeast-1",
+		"sao paulo": "sa-east-1",
+	}
+	for keyword, want := range tests {
+		got := FilterServerRegions(servers, keyword)
+		if len(got) != 1 || got[0].Code != want {
+			t.Errorf("关键字 %q 筛选结果为 %+v，期望 %s", keyword, got, want)
+		}
+	}
+	if got := FilterServerRegions(servers, "us-east"); len(got) != 2 {
+		t.Errorf("us-east 应匹配两个区服: %+v", got)
+	}
+	if got := FilterServerRegions(servers, "火星"); len(got) != 0 {
+		t.Errorf("不应匹配任何区服: %+v", got)
+	}
+
+	data, err := RenderServerImage(servers)
+	if err != nil {
+		t.Fatalf("绘制区服图片失败: %v", err)
+	}
+	img, err := jpeg.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("解码区服图片失败: %v", err)
+	}
+	if want := serverHeaderHeight + len(servers)*serverRowHeight + serverFooterHeight; img.Bounds().Dy() != want {
+		t.Errorf("图片高度为 %d，期望 %d", img.Bounds().Dy(), want)
+	}
+}
@@ -0,0 +1,332 @@
+package apexapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/newton-miku/apexQQbot/tools"
+)
+
+// 服务器状态的缓存时长
+const serverStatusCacheDuration = 2 * time.Minute
+
+const (
+	statusImageWidth   = 1200
+	statusHeaderHeight = 150
+	statusRowHeight    = 60
+	statusFooterHeight = 70
+	statusNameWidth    = 250
+)
+
+// ServiceState 服务在某个区域的运行状态
+type ServiceState string
+
+const (
+	ServiceUp      ServiceState = "UP"
+	ServiceSlow    ServiceState = "SLOW"
+	ServiceDown    ServiceState = "DOWN"
+	ServiceUnknown ServiceState = ""
+)
+
+// String 状态的中文描述
+func (s ServiceState) String() string {
+	switch s {
+	case ServiceUp:
+		return "正常"
+	case ServiceSlow:
+		return "缓慢"
+	case ServiceDown:
+		return "故障"
+	default:
+		return "未知"
+	}
+}
+
+// Color 状态对应的颜色
+func (s ServiceState) Color() color.RGBA {
+	switch s {
+	case ServiceUp:
+		return color.RGBA{70, 170, 90, 255}
+	case ServiceSlow:
+		return color.RGBA{220, 170, 50, 255}
+	case ServiceDown:
+		return color.RGBA{210, 60, 60, 255}
+	default:
+		return color.RGBA{90, 90, 100, 255}
+	}
+}
+
+// severity 用于选出最差的状态
+func (s ServiceState) severity() int {
+	switch s {
+	case ServiceUp:
+		return 0
+	case ServiceSlow:
+		return 2
+	case ServiceDown:
+		return 3
+	default:
+		return 1
+	}
+}
+
+// RegionStatus 服务在单个区域的状态
+type RegionStatus struct {
+	Region       string       `json:"-"`
+	State        ServiceState `json:"Status"`
+	HTTPCode     int          `json:"HTTPCode"`
+	ResponseTime int          `json:"ResponseTime"` // 毫秒
+}
+
+// ServiceStatus 单项服务在各区域的状态
+type ServiceStatus struct {
+	Key     string
+	Name    string
+	Regions map[string]RegionStatus
+}
+
+// ServerStatus 服务器状态
+type ServerStatus struct {
+	Services  []ServiceStatus
+	UpdatedAt time.Time
+}
+
+// 展示的服务及其中文名（按展示顺序）
+var statusServices = []struct {
+	key  string
+	name string
+}{
+	{"Origin_login", "Origin 登录"},
+	{"EA_accounts", "EA 账号"},
+	{"EA_novafusion", "匹配服务器"},
+	{"ApexOauth_Crossplay", "跨平台"},
+}
+
+// 区域展示顺序与中文名，未列出的区域按名称排在最后
+var statusRegions = []struct {
+	key  string
+	name string
+}{
+	{"Asia", "亚洲"},
+	{"EU-West", "欧洲西部"},
+	{"EU-East", "欧洲东部"},
+	{"US-West", "美国西部"},
+	{"US-Central", "美国中部"},
+	{"US-East", "美国东部"},
+	{"SouthAmerica", "南美"},
+}
+
+// regionName 区域的中文名
+func regionName(region string) string {
+	for _, r := range statusRegions {
+		if r.key == region {
+			return r.name
+		}
+	}
+	return region
+}
+
+// Regions 全部服务出现过的区域（按展示顺序）
+func (s ServerStatus) Regions() []string {
+	seen := make(map[string]bool)
+	for _, service := range s.Services {
+		for region := range service.Regions {
+			seen[region] = true
+		}
+	}
+	var regions []string
+	for _, r := range statusRegions {
+		if seen[r.key] {
+			regions = append(regions, r.key)
+			delete(seen, r.key)
+		}
+	}
+	var rest []string
+	for region := range seen {
+		rest = append(rest, region)
+	}
+	sort.Strings(rest)
+	return append(regions, rest...)
+}
+
+// Worst 服务在全部区域中最差的状态
+func (s ServiceStatus) Worst() ServiceState {
+	if len(s.Regions) == 0 {
+		return ServiceUnknown
+	}
+	worst := ServiceUp
+	for _, r := range s.Regions {
+		if r.State.severity() > worst.severity() {
+			worst = r.State
+		}
+	}
+	return worst
+}
+
+var (
+	cachedServerStatus   *ServerStatus
+	serverStatusCachedAt time.Time
+	serverStatusLock     sync.RWMutex
+)
+
+// GetServerStatus 获取服务器状态（带缓存）
+func GetServerStatus(ctx context.Context) (*ServerStatus, error) {
+	serverStatusLock.RLock()
+	if cachedServerStatus != nil && time.Since(serverStatusCachedAt) < serverStatusCacheDuration {
+		result := cachedServerStatus
+		serverStatusLock.RUnlock()
+		return result, nil
+	}
+	serverStatusLock.RUnlock()
+
+	return GetServerStatusFromAPI(ctx)
+}
+
+// GetServerStatusFromAPI 获取服务器状态（不带缓存）：配置了 status_url 时使用该地址（结构同 apexlegendsapi 的 servers 接口），
+// 否则按数据来源链依次尝试
+func GetServerStatusFromAPI(ctx context.Context) (*ServerStatus, error) {
+	var status *ServerStatus
+	var err error
+	if urlStr := GetAPIConfig().StatusURL; urlStr != "" {
+		var body []byte
+		body, err = fetchBody(withEndpoint(ctx, endpointStatus), urlStr, 64<<10)
+		if err == nil {
+			status, err = parseServerStatus(body)
+		}
+	} else {
+		status, err = GetStatsProvider().ServerStatus(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	serverStatusLock.Lock()
+	cachedServerStatus = status
+	serverStatusCachedAt = time.Now()
+	serverStatusLock.Unlock()
+	return status, nil
+}
+
+// parseServerStatus 解析服务器状态，只保留 statusServices 中列出的服务
+func parseServerStatus(body []byte) (*ServerStatus, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidJSON, err)
+	}
+
+	status := &ServerStatus{UpdatedAt: time.Now()}
+	for _, s := range statusServices {
+		data, ok := raw[s.key]
+		if !ok {
+			continue
+		}
+		var regions map[string]RegionStatus
+		if err := json.Unmarshal(data, &regions); err != nil {
+			continue
+		}
+		for region, r := range regions {
+			r.Region = region
+			r.State = ServiceState(strings.ToUpper(string(r.State)))
+			regions[region] = r
+		}
+		status.Services = append(status.Services, ServiceStatus{Key: s.key, Name: s.name, Regions: regions})
+	}
+	if len(status.Services) == 0 {
+		return nil, fmt.Errorf("服务器状态数据为空")
+	}
+	return status, nil
+}
+
+// FormatServerStatusSummary 服务器状态的文字摘要，列出状态异常的服务
+func FormatServerStatusSummary(status *ServerStatus) string {
+	var problems []string
+	for _, service := range status.Services {
+		var regions []string
+		for _, region := range status.Regions() {
+			if r, ok := service.Regions[region]; ok && (r.State == ServiceSlow || r.State == ServiceDown) {
+				regions = append(regions, fmt.Sprintf("%s（%s）", regionName(region), r.State))
+			}
+		}
+		if len(regions) > 0 {
+			problems = append(problems, fmt.Sprintf("%s：%s", service.Name, strings.Join(regions, "、")))
+		}
+	}
+	if len(problems) == 0 {
+		return "各项服务运行正常，如无法匹配请检查自己的网络"
+	}
+	return "以下服务存在异常：\n" + strings.Join(problems, "\n")
+}
+
+// RenderServerStatusImage 绘制服务器状态表：每行一项服务，每列一个区域，按状态着色
+func RenderServerStatusImage(status *ServerStatus) ([]byte, error) {
+	regions := status.Regions()
+	if len(status.Services) == 0 || len(regions) == 0 {
+		return nil, fmt.Errorf("没有可展示的服务器状态")
+	}
+	fontPath, err := getMapFontPath()
+	if err != nil {
+		return nil, err
+	}
+
+	height := statusHeaderHeight + len(status.Services)*statusRowHeight + statusFooterHeight
+	dst := image.NewRGBA(image.Rect(0, 0, statusImageWidth, height))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(cardBackground), image.Point{}, draw.Src)
+
+	colWidth := (statusImageWidth - statusNameWidth - 20) / len(regions)
+	colCenter := func(i int) int { return statusNameWidth + colWidth*i + colWidth/2 }
+
+	lines := []tools.TextLine{
+		{Text: "服务器状态", Size: 40, X: 30, Y: 55, FontPath: fontPath, Color: cardValueColor},
+		{Text: status.UpdatedAt.Local().Format("2006-01-02 15:04"), Size: 22, X: statusImageWidth - 30, Y: 50, Alignment: "right", FontPath: fontPath, Color: cardDimColor},
+	}
+	for i, region := range regions {
+		lines = append(lines, tools.TextLine{Text: regionName(region), Size: 24, X: colCenter(i), Y: 125, Alignment: "center", FontPath: fontPath, Color: cardLabelColor})
+	}
+
+	for row, service := range status.Services {
+		top := statusHeaderHeight + row*statusRowHeight
+		if row%2 == 0 {
+			draw.Draw(dst, image.Rect(0, top, statusImageWidth, top+statusRowHeight), image.NewUniform(cardStripe), image.Point{}, draw.Src)
+		}
+		lines = append(lines, tools.TextLine{Text: service.Name, Size: 28, X: 30, Y: top + 40, FontPath: fontPath, Color: cardValueColor})
+
+		for i, region := range regions {
+			r, ok := service.Regions[region]
+			state := ServiceUnknown
+			if ok {
+				state = r.State
+			}
+			cell := image.Rect(colCenter(i)-colWidth/2+8, top+10, colCenter(i)+colWidth/2-8, top+statusRowHeight-10)
+			draw.Draw(dst, cell, image.NewUniform(state.Color()), image.Point{}, draw.Src)
+
+			text := state.String()
+			if ok && state != ServiceDown && r.ResponseTime > 0 {
+				text = fmt.Sprintf("%dms", r.ResponseTime)
+			}
+			lines = append(lines, tools.TextLine{Text: text, Size: 22, X: colCenter(i), Y: top + 38, Alignment: "center", FontPath: fontPath, Color: color.RGBA{255, 255, 255, 255}})
+		}
+	}
+
+	// 图例
+	legendTop := height - statusFooterHeight + 20
+	for i, state := range []ServiceState{ServiceUp, ServiceSlow, ServiceDown, ServiceUnknown} {
+		x := 30 + i*160
+		draw.Draw(dst, image.Rect(x, legendTop, x+30, legendTop+30), image.NewUniform(state.Color()), image.Point{}, draw.Src)
+		lines = append(lines, tools.TextLine{Text: state.String(), Size: 22, X: x + 40, Y: legendTop + 24, FontPath: fontPath, Color: cardLabelColor})
+	}
+	tools.AddTextToImageInPlace(dst, lines)
+
+	data, err := encodeJPEG(dst)
+	if err != nil {
+		return nil, fmt.Errorf("编码图片失败: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,91 @@
+package apexapi
+
+import (
+	"bytes"
+	"context"
+	"image/jpeg"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const sampleServerStatus = `{
+  "Origin_login": {
+    "EU-West": {"Status": "UP", "HTTPCode": 200, "ResponseTime": 80, "QueryTimestamp": 1700000000},
+    "Asia": {"Status": "SLOW", "HTTPCode": 200, "ResponseTime": 900, "QueryTimestamp": 1700000000}
+  },
+  "EA_novafusion": {
+    "EU-West": {"Status": "UP", "HTTPCode": 200, "ResponseTime": 50, "QueryTimestamp": 1700000000},
+    "Asia": {"Status": "DOWN", "HTTPCode": 0, "ResponseTime": 0, "QueryTimestamp": 1700000000}
+  },
+  "selfCoreTest": {"Status-website": {"Status": "UP"}},
+  "otherPlatforms": {"Playstation-Network": {"Status": "UP"}}
+}`
+
+func TestParseServerStatus(t *testing.T) {
+	status, err := parseServerStatus([]byte(sampleServerStatus))
+	if err != nil {
+		t.Fatalf("parseServerStatus: %v", err)
+	}
+	if len(status.Services) != 2 || status.Services[0].Key != "Origin_login" || status.Services[1].Key != "EA_novafusion" {
+		t.Fatalf("services = %+v", status.Services)
+	}
+	if regions := status.Regions(); len(regions) != 2 || regions[0] != "Asia" || regions[1] != "EU-West" {
+		t.Errorf("regions = %v", regions)
+	}
+	if got := status.Services[0].Worst(); got != ServiceSlow {
+		t.Errorf("Origin worst = %q, want SLOW", got)
+	}
+	if got := status.Services[1].Worst(); got != ServiceDown {
+		t.Errorf("matchmaking worst = %q, want DOWN", got)
+	}
+
+	summary := FormatServerStatusSummary(status)
+	for _, want := range []string{"Origin 登录：亚洲（缓慢）", "匹配服务器：亚洲（故障）"} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("summary %q missing %q", summary, want)
+		}
+	}
+
+	data, err := RenderServerStatusImage(status)
+	if err != nil {
+		t.Fatalf("RenderServerStatusImage: %v", err)
+	}
+	if _, err := jpeg.Decode(bytes.NewReader(data)); err != nil {
+		t.Errorf("decode image: %v", err)
+	}
+}
+
+func TestParseServerStatusEmpty(t *testing.T) {
+	if _, err := parseServerStatus([]byte(`{"selfCoreTest": {}}`)); err == nil {
+		t.Error("expected error for empty status")
+	}
+}
+
+func TestServerStatusFromStatusURL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(sampleServerStatus))
+	}))
+	defer srv.Close()
+
+	configMx.Lock()
+	saved := ApiConf
+	ApiConf.StatusURL = srv.URL
+	ApiConf.ApiToken = ""
+	configMx.Unlock()
+	t.Cleanup(func() {
+		configMx.Lock()
+		ApiConf = saved
+		configMx.Unlock()
+	})
+
+	// 配置了 status_url 时不需要 apitoken
+	status, err := GetServerStatusFromAPI(context.Background())
+	if err != nil {
+		t.Fatalf("GetServerStatusFromAPI: %v", err)
+	}
+	if len(status.Services) == 0 {
+		t.Errorf("status = %+v", status)
+	}
+}
@@ -3,12 +3,15 @@ package apexapi
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"regexp"
 	"sync"
 	"time"
+
+	botlog "github.com/tencent-connect/botgo/log"
 )
 
 const (
@@ -84,12 +87,25 @@ func GetStoreCountdown() (*StoreCountdown, error) {
 	if cachedStoreCountdown != nil && time.Now().Before(storeCacheExpiresAt) {
 		result := cachedStoreCountdown
 		storeCacheLock.RUnlock()
+		recordCache("store", true)
 		return result, nil
 	}
 	storeCacheLock.RUnlock()
+	recordCache("store", false)
 
 	// 缓存无效，重新获取
-	return GetStoreCountdownFromAPI()
+	countdown, err := GetStoreCountdownFromAPI()
+	if err != nil && errors.Is(err, ErrCircuitOpen) {
+		// 上游熔断期间返回过期的缓存，避免持续请求
+		storeCacheLock.RLock()
+		stale := cachedStoreCountdown
+		storeCacheLock.RUnlock()
+		if stale != nil {
+			botlog.Warnf("商店接口熔断中，使用过期的缓存数据: %v", err)
+			return stale, nil
+		}
+	}
+	return countdown, err
 }
 
 // getStoreNonce 获取有效的 nonce（带缓存）
@@ -103,8 +119,11 @@ func getStoreNonce() (string, error) {
 	}
 
 	// 从主页获取新的 nonce
-	client := GetHTTPClient(15 * time.Second)
-	resp, err := client.Get("https://apexitemstore.com")
+	req, err := http.NewRequestWithContext(withEndpoint(context.Background(), endpointStore), "GET", "https://apexitemstore.com", nil)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrRequestCreateFailed, err)
+	}
+	resp, err := GetHTTPClient(15 * time.Second).Do(req)
 	if err != nil {
 		return "", fmt.Errorf("fetch homepage failed: %w", err)
 	}
@@ -141,7 +160,7 @@ func GetStoreCountdownFromAPI() (*StoreCountdown, error) {
 	reqURL := fmt.Sprintf("%s?action=scd_query_next_event&smartcountdown_nonce=%s&unique_ts=%d&deadline=&import_config=scd_easy_recurrence%%3A%%3A2&countdown_to_end=0&countup_limit=0",
 		storeAPIURL, nonce, time.Now().UnixMilli())
 
-	req, err := http.NewRequestWithContext(context.Background(), "GET", reqURL, nil)
+	req, err := http.NewRequestWithContext(withEndpoint(context.Background(), endpointStore), "GET", reqURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrRequestCreateFailed, err)
 	}
@@ -161,12 +180,12 @@ func GetStoreCountdownFromAPI() (*StoreCountdown, error) {
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %v", ErrRequestFailed, err)
+		return nil, wrapRequestError(err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, ErrStatusCode(resp.StatusCode)
+		return nil, StatusError(resp.StatusCode)
 	}
 
 	limitReader := io.LimitReader(resp.Body, 10<<10)
@@ -0,0 +1,152 @@
+package apexapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/newton-miku/apexQQbot/tools"
+)
+
+// 追踪器名称翻译器（修改 asset/tracker_dict.json 后自动生效）
+var (
+	trackerTranslator *tools.Translator
+	trackerDictPath   = "./asset/tracker_dict.json"
+	trackerOnce       sync.Once
+)
+
+func getTrackerTranslator() *tools.Translator {
+	trackerOnce.Do(func() {
+		trans, err := tools.NewTranslator(trackerDictPath)
+		if err != nil {
+			// 静默失败，使用英文原名
+			return
+		}
+		trackerTranslator = trans
+	})
+	return trackerTranslator
+}
+
+// GetTrackerName 获取追踪器名称（中文）
+func GetTrackerName(name string) string {
+	trans := getTrackerTranslator()
+	if trans == nil {
+		return name
+	}
+	return trans.Translate(name)
+}
+
+// StatCategory 数据项分类
+type StatCategory int
+
+const (
+	StatKills StatCategory = iota
+	StatDamage
+	StatWins
+	StatOther
+)
+
+// statCategories 展示顺序
+var statCategories = []StatCategory{StatKills, StatDamage, StatWins, StatOther}
+
+// String 分类的中文名
+func (c StatCategory) String() string {
+	switch c {
+	case StatKills:
+		return "击杀"
+	case StatDamage:
+		return "伤害"
+	case StatWins:
+		return "胜场"
+	default:
+		return "其他"
+	}
+}
+
+// Category 根据接口中的 key 或英文名判断数据项的分类
+func (s LegendStatItem) Category() StatCategory {
+	id := strings.ToLower(s.Key + " " + s.Name)
+	switch {
+	case strings.Contains(id, "damage"):
+		return StatDamage
+	case strings.Contains(id, "win") && !strings.Contains(id, "winning"):
+		return StatWins
+	case strings.Contains(id, "kill") || strings.Contains(id, "winning"):
+		return StatKills
+	default:
+		return StatOther
+	}
+}
+
+// Number 将数据值解析为数字，接口可能返回数字或带千分位的字符串
+func (s LegendStatItem) Number() (float64, bool) {
+	switch v := s.Value.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(v), ",", ""), 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// DisplayName 数据项的中文名
+func (s LegendStatItem) DisplayName() string {
+	return GetTrackerName(s.Name)
+}
+
+// DisplayValue 数据值的展示形式，数字使用千分位分隔
+func (s LegendStatItem) DisplayValue() string {
+	if n, ok := s.Number(); ok {
+		return FormatNumber(n)
+	}
+	return fmt.Sprint(s.Value)
+}
+
+// FormatNumber 使用千分位分隔格式化数字，非整数保留两位小数
+func FormatNumber(n float64) string {
+	sign := ""
+	if n < 0 {
+		sign = "-"
+		n = -n
+	}
+
+	// 先整体四舍五入到两位小数，避免小数部分进位（如 1.996）时与整数部分脱节
+	digits, frac, _ := strings.Cut(strconv.FormatFloat(n, 'f', 2, 64), ".")
+	if digits == "0" && frac == "00" {
+		sign = ""
+	}
+	var b strings.Builder
+	for i, c := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(c)
+	}
+
+	result := sign + b.String()
+	if frac != "00" {
+		result += "." + frac
+	}
+	return result
+}
+
+// groupStatItems 按分类分组数据项，保持原有顺序
+func groupStatItems(items []LegendStatItem) map[StatCategory][]LegendStatItem {
+	groups := make(map[StatCategory][]LegendStatItem)
+	for _, item := range items {
+		c := item.Category()
+		groups[c] = append(groups[c], item)
+	}
+	return groups
+}
@@ -0,0 +1,303 @@
+package apexapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	botlog "github.com/tencent-connect/botgo/log"
+)
+
+const (
+	// 单次请求的最大尝试次数（包含第一次）
+	retryMaxAttempts = 3
+	// 第一次重试的基础等待时间，之后每次翻倍
+	retryBaseDelay = 500 * time.Millisecond
+	// 单次等待的上限，Retry-After 超过该值时不再重试
+	retryMaxDelay = 10 * time.Second
+	// 请求没有总超时时，单次尝试的超时
+	retryAttemptTimeout = 10 * time.Second
+	// 单次尝试超时的下限，避免剩余时间过短导致尝试必然失败
+	retryMinAttemptTimeout = 2 * time.Second
+	// 同一主机连续失败多少次后熔断
+	breakerThreshold = 5
+	// 熔断持续时间，之后放行一次试探请求
+	breakerCooldown = 30 * time.Second
+)
+
+// breakerState 熔断器状态
+type breakerState int
+
+const (
+	breakerClosed   breakerState = iota // 正常放行
+	breakerOpen                         // 熔断中，直接拒绝请求
+	breakerHalfOpen                     // 冷却结束，正在进行试探请求
+)
+
+// attemptOutcome 一次请求的结果
+type attemptOutcome int
+
+const (
+	outcomeSuccess attemptOutcome = iota // 收到正常响应（包括 404 等客户端错误）
+	outcomeFailure                       // 网络错误、单次尝试超时、5xx 或 429
+	outcomeAborted                       // 调用方取消或总超时，不计入主机健康状况
+)
+
+// circuitBreaker 单个主机的熔断器
+type circuitBreaker struct {
+	mu       sync.Mutex
+	state    breakerState
+	failures int
+	openedAt time.Time
+}
+
+// allow 判断是否放行请求；熔断冷却结束后只放行一个试探请求
+func (b *circuitBreaker) allow(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if now.Sub(b.openedAt) < breakerCooldown {
+			return false
+		}
+		b.state = breakerHalfOpen
+		return true
+	case breakerHalfOpen:
+		return false
+	default:
+		return true
+	}
+}
+
+// record 记录请求结果
+func (b *circuitBreaker) record(outcome attemptOutcome, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch outcome {
+	case outcomeSuccess:
+		b.state = breakerClosed
+		b.failures = 0
+	case outcomeFailure:
+		b.failures++
+		if b.state == breakerHalfOpen || b.failures >= breakerThreshold {
+			b.state = breakerOpen
+			b.openedAt = now
+		}
+	case outcomeAborted:
+		// 试探请求被取消时恢复为熔断状态，冷却已结束，下一个请求会重新试探
+		if b.state == breakerHalfOpen {
+			b.state = breakerOpen
+		}
+	}
+}
+
+// isOpen 熔断器是否处于熔断或试探状态
+func (b *circuitBreaker) isOpen() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state != breakerClosed
+}
+
+// retryTransport 为上游请求提供指数退避重试与按主机的熔断
+type retryTransport struct {
+	base http.RoundTripper
+
+	mu       sync.Mutex
+	breakers map[string]*circuitBreaker
+
+	// 等待函数，测试时可替换
+	sleep func(ctx context.Context, d time.Duration) error
+	// 单次尝试的超时设置，测试时可调小
+	attemptTimeout    time.Duration
+	minAttemptTimeout time.Duration
+}
+
+func newRetryTransport(base http.RoundTripper) *retryTransport {
+	return &retryTransport{
+		base:              base,
+		breakers:          make(map[string]*circuitBreaker),
+		sleep:             sleepContext,
+		attemptTimeout:    retryAttemptTimeout,
+		minAttemptTimeout: retryMinAttemptTimeout,
+	}
+}
+
+// breaker 获取主机对应的熔断器
+func (t *retryTransport) breaker(host string) *circuitBreaker {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	b, ok := t.breakers[host]
+	if !ok {
+		b = &circuitBreaker{}
+		t.breakers[host] = b
+	}
+	return b
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	breaker := t.breaker(req.URL.Host)
+	replayable := isReplayable(req)
+
+	for attempt := 0; ; attempt++ {
+		if !breaker.allow(time.Now()) {
+			upstreamRequests.WithLabelValues(endpointFrom(ctx), "circuit_open").Inc()
+			return nil, fmt.Errorf("%w: %s", ErrCircuitOpen, req.URL.Host)
+		}
+
+		// 每次尝试使用单独的超时，超时后仍可在总超时内重试
+		attemptCtx, cancel := context.WithTimeout(ctx, t.attemptTimeoutFor(ctx, retryMaxAttempts-attempt, time.Now()))
+		outReq := req.WithContext(attemptCtx)
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				cancel()
+				return nil, err
+			}
+			outReq = req.Clone(attemptCtx)
+			outReq.Body = body
+		}
+
+		start := time.Now()
+		resp, err := t.base.RoundTrip(outReq)
+		observeUpstream(ctx, start, resp, err)
+		if resp != nil {
+			// 响应体读取完毕关闭时才结束本次尝试
+			resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+		} else {
+			cancel()
+		}
+		outcome := classifyAttempt(ctx, resp, err)
+		breaker.record(outcome, time.Now())
+
+		if outcome != outcomeFailure || !replayable || attempt+1 >= retryMaxAttempts {
+			return resp, err
+		}
+
+		delay := backoffDelay(attempt)
+		if resp != nil {
+			if after, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+				if after > retryMaxDelay {
+					return resp, err
+				}
+				delay = after
+			}
+			// 丢弃本次响应，连接可以被复用
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+		}
+
+		botlog.Debugf("请求 %s 失败，%v 后第 %d 次重试: %v", req.URL.Host, delay, attempt+1, attemptError(resp, err))
+		if err := t.sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// isReplayable 只重试幂等且请求体可以重放的请求。
+// 其他方法的请求可按 net/http 的约定设置 Idempotency-Key 或 X-Idempotency-Key 头（值为 nil 时不会发送）标记为幂等
+func isReplayable(req *http.Request) bool {
+	_, keyed := req.Header["Idempotency-Key"]
+	_, xKeyed := req.Header["X-Idempotency-Key"]
+	idempotent := req.Method == http.MethodGet || req.Method == http.MethodHead || keyed || xKeyed
+	return idempotent && (req.Body == nil || req.Body == http.NoBody || req.GetBody != nil)
+}
+
+// attemptTimeoutFor 单次尝试的超时：将剩余的总时间平分给剩余的尝试次数，没有总超时时使用固定值
+func (t *retryTransport) attemptTimeoutFor(ctx context.Context, attemptsLeft int, now time.Time) time.Duration {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return t.attemptTimeout
+	}
+	return max(deadline.Sub(now)/time.Duration(max(attemptsLeft, 1)), t.minAttemptTimeout)
+}
+
+// cancelOnClose 关闭响应体时取消本次尝试的 context
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
+
+// classifyAttempt 判断一次请求的结果，ctx 为调用方的 context：调用方取消或总超时视为中止，单次尝试超时视为失败
+func classifyAttempt(ctx context.Context, resp *http.Response, err error) attemptOutcome {
+	if err != nil {
+		if ctx.Err() != nil || errors.Is(err, context.Canceled) {
+			return outcomeAborted
+		}
+		return outcomeFailure
+	}
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		return outcomeFailure
+	}
+	return outcomeSuccess
+}
+
+// attemptError 用于日志的失败原因
+func attemptError(resp *http.Response, err error) error {
+	if err != nil {
+		return err
+	}
+	return StatusError(resp.StatusCode)
+}
+
+// backoffDelay 第 attempt 次失败后的等待时间：指数增长，并在后半段随机抖动以错开并发请求
+func backoffDelay(attempt int) time.Duration {
+	d := min(retryBaseDelay<<attempt, retryMaxDelay)
+	return d/2 + rand.N(d/2+1)
+}
+
+// parseRetryAfter 解析 Retry-After 头（秒数或 HTTP 日期）
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0), true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return max(t.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// wrapRequestError 包装请求失败的错误；熔断时保留 ErrCircuitOpen，以便调用方改用缓存数据。
+// 错误信息中的 URL 去掉查询参数，避免 Token 等参数出现在日志里
+func wrapRequestError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		address, _, _ := strings.Cut(urlErr.URL, "?")
+		err = fmt.Errorf("%s %q: %w", urlErr.Op, address, urlErr.Err)
+	}
+	if errors.Is(err, ErrCircuitOpen) {
+		return err
+	}
+	return fmt.Errorf("%w: %v", ErrRequestFailed, err)
+}
@@ -0,0 +1,275 @@
+package apexapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestTransport 创建不实际等待的重试层，并记录每次等待的时长
+func newTestTransport(delays *[]time.Duration) *retryTransport {
+	t := newRetryTransport(http.DefaultTransport)
+	t.sleep = func(ctx context.Context, d time.Duration) error {
+		*delays = append(*delays, d)
+		return nil
+	}
+	return t
+}
+
+func TestRetryTransportRetries(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch calls.Add(1) {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			w.Header().Set("Retry-After", "2")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.Write([]byte("ok"))
+		}
+	}))
+	defer srv.Close()
+
+	var delays []time.Duration
+	client := &http.Client{Transport: newTestTransport(&delays)}
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || calls.Load() != 3 {
+		t.Fatalf("status = %d, calls = %d", resp.StatusCode, calls.Load())
+	}
+	if len(delays) != 2 || delays[0] < retryBaseDelay/2 || delays[0] > retryBaseDelay || delays[1] != 2*time.Second {
+		t.Errorf("delays = %v", delays)
+	}
+}
+
+func TestRetryTransportAttemptTimeout(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			// 第一次请求一直等到客户端放弃
+			<-r.Context().Done()
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	var delays []time.Duration
+	transport := newTestTransport(&delays)
+	transport.attemptTimeout = 100 * time.Millisecond
+	client := &http.Client{Transport: transport}
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || calls.Load() != 2 || len(delays) != 1 {
+		t.Fatalf("status = %d, calls = %d, delays = %v", resp.StatusCode, calls.Load(), delays)
+	}
+}
+
+func TestRetryTransportIdempotentPost(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	var delays []time.Duration
+	client := &http.Client{Transport: newTestTransport(&delays)}
+
+	// 未标记幂等的 POST 不重试
+	resp, err := client.Post(srv.URL, "", nil)
+	if err != nil {
+		t.Fatalf("Post: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadGateway || calls.Load() != 1 {
+		t.Fatalf("status = %d, calls = %d", resp.StatusCode, calls.Load())
+	}
+
+	// 标记为幂等后重试，且不会发送该请求头
+	calls.Store(0)
+	req, _ := http.NewRequest(http.MethodPost, srv.URL, nil)
+	req.Header["Idempotency-Key"] = nil
+	resp, err = client.Do(req)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || calls.Load() != 2 {
+		t.Errorf("status = %d, calls = %d", resp.StatusCode, calls.Load())
+	}
+}
+
+func TestAttemptTimeoutFor(t *testing.T) {
+	transport := newRetryTransport(http.DefaultTransport)
+	now := time.Now()
+	if got := transport.attemptTimeoutFor(context.Background(), 3, now); got != retryAttemptTimeout {
+		t.Errorf("no deadline = %v", got)
+	}
+
+	ctx, cancel := context.WithDeadline(context.Background(), now.Add(15*time.Second))
+	defer cancel()
+	if got := transport.attemptTimeoutFor(ctx, 3, now); got != 5*time.Second {
+		t.Errorf("3 attempts left = %v", got)
+	}
+	if got := transport.attemptTimeoutFor(ctx, 1, now.Add(14*time.Second)); got != retryMinAttemptTimeout {
+		t.Errorf("near deadline = %v", got)
+	}
+}
+
+func TestRetryTransportNoRetry(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if r.URL.Path == "/slow" {
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	var delays []time.Duration
+	client := &http.Client{Transport: newTestTransport(&delays)}
+
+	// 客户端错误不重试
+	resp, err := client.Get(srv.URL + "/missing")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound || calls.Load() != 1 {
+		t.Errorf("status = %d, calls = %d", resp.StatusCode, calls.Load())
+	}
+
+	// Retry-After 过长时直接返回
+	resp, err = client.Get(srv.URL + "/slow")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusTooManyRequests || calls.Load() != 2 || len(delays) != 0 {
+		t.Errorf("status = %d, calls = %d, delays = %v", resp.StatusCode, calls.Load(), delays)
+	}
+}
+
+func TestRetryTransportCircuitBreaker(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	var delays []time.Duration
+	client := &http.Client{Transport: newTestTransport(&delays)}
+
+	var err error
+	for i := 0; i < breakerThreshold && err == nil; i++ {
+		var resp *http.Response
+		if resp, err = client.Get(srv.URL); err == nil {
+			resp.Body.Close()
+		}
+	}
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("连续失败后应熔断，实际为 %v", err)
+	}
+	if calls.Load() != breakerThreshold {
+		t.Errorf("熔断前应请求 %d 次，实际为 %d", breakerThreshold, calls.Load())
+	}
+	if !IsRetryable(err) || UserMessage(wrapRequestError(err)) == "" {
+		t.Errorf("熔断错误应可稍后重试: %v", err)
+	}
+
+	// 熔断期间不再请求上游
+	before := calls.Load()
+	if _, err := client.Get(srv.URL); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("熔断期间应直接返回错误，实际为 %v", err)
+	}
+	if calls.Load() != before {
+		t.Error("熔断期间不应请求上游")
+	}
+}
+
+func TestCircuitBreakerHalfOpen(t *testing.T) {
+	var b circuitBreaker
+	now := time.Now()
+	for range breakerThreshold {
+		b.record(outcomeFailure, now)
+	}
+	if b.allow(now) {
+		t.Fatal("熔断期间不应放行")
+	}
+
+	// 冷却结束后只放行一个试探请求
+	later := now.Add(breakerCooldown)
+	if !b.allow(later) || b.allow(later) {
+		t.Fatal("冷却结束后应只放行一个试探请求")
+	}
+	// 试探被取消时，下一个请求可以重新试探
+	b.record(outcomeAborted, later)
+	if !b.allow(later) {
+		t.Fatal("试探取消后应允许重新试探")
+	}
+	// 试探失败重新熔断
+	b.record(outcomeFailure, later)
+	if b.allow(later.Add(time.Second)) {
+		t.Fatal("试探失败后应重新熔断")
+	}
+	// 试探成功后恢复
+	end := later.Add(breakerCooldown)
+	if !b.allow(end) {
+		t.Fatal("冷却结束后应放行试探请求")
+	}
+	b.record(outcomeSuccess, end)
+	if !b.allow(end) || b.isOpen() {
+		t.Fatal("试探成功后应恢复正常")
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	if d, ok := parseRetryAfter("5", now); !ok || d != 5*time.Second {
+		t.Errorf("seconds: %v %v", d, ok)
+	}
+	if d, ok := parseRetryAfter(now.Add(time.Minute).Format(http.TimeFormat), now); !ok || d != time.Minute {
+		t.Errorf("date: %v %v", d, ok)
+	}
+	if _, ok := parseRetryAfter("soon", now); ok {
+		t.Error("invalid value should not parse")
+	}
+}
+
+func TestWrapRequestErrorRedactsQuery(t *testing.T) {
+	err := wrapRequestError(&url.Error{Op: "Get", URL: "https://api.example.com/bridge?auth=secret", Err: errors.New("connection reset")})
+	if !errors.Is(err, ErrRequestFailed) {
+		t.Errorf("应包装为 ErrRequestFailed: %v", err)
+	}
+	if msg := err.Error(); strings.Contains(msg, "secret") || !strings.Contains(msg, "api.example.com/bridge") {
+		t.Errorf("错误信息应去掉查询参数: %s", msg)
+	}
+
+	open := wrapRequestError(&url.Error{Op: "Get", URL: "https://api.example.com/?auth=secret", Err: ErrCircuitOpen})
+	if !errors.Is(open, ErrCircuitOpen) || strings.Contains(open.Error(), "secret") {
+		t.Errorf("熔断错误应保留 ErrCircuitOpen 并去掉查询参数: %v", open)
+	}
+}
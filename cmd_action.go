@@ -1,23 +1,25 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
-	"strings"
-	"time"
 
 	"github.com/go-resty/resty/v2"
+	"github.com/tencent-connect/botgo/constant"
 	"github.com/tencent-connect/botgo/dto"
 	botlog "github.com/tencent-connect/botgo/log"
 )
 
-type ImgRichData struct {
-	Group_openid string `json:"group_openid"`
-	File_type    int    `json:"file_type"`
-	File_data    string `json:"file_data"`
-	Srv_send_msg bool   `json:"srv_send_msg"`
+// RichMediaUpload 富媒体上传请求体（群与 C2C 通用），URL 与 FileData 二选一
+type RichMediaUpload struct {
+	FileType   int    `json:"file_type"`
+	URL        string `json:"url,omitempty"`
+	FileData   string `json:"file_data,omitempty"`
+	SrvSendMsg bool   `json:"srv_send_msg"`
 }
 
 func (p Processor) setEmoji(ctx context.Context, channelID string, messageID string) {
@@ -56,127 +58,159 @@ func (p Processor) sendChannelReply(ctx context.Context, channelID string, toCre
 	return nil
 }
 
+// postWithSeq 为被动消息分配 msg_seq 后发送，若被平台去重则换用新的序号重试一次
+func postWithSeq(toCreate dto.APIMessage, post func(dto.APIMessage) error) error {
+	assignMsgSeq(toCreate)
+	err := post(toCreate)
+	if err != nil && isMsgSeqDuplicated(err) {
+		dedupMessages.Inc()
+		botlog.Warnf("消息序号重复，使用新序号重试: %v", err)
+		assignMsgSeq(toCreate)
+		err = post(toCreate)
+	}
+	return err
+}
+
+// postGroupMessage 发送群消息（自动分配 msg_seq）
+func (p Processor) postGroupMessage(ctx context.Context, groupID string, toCreate dto.APIMessage) error {
+	return postWithSeq(toCreate, func(msg dto.APIMessage) error {
+		_, err := p.api.PostGroupMessage(ctx, groupID, msg)
+		return err
+	})
+}
+
+// postC2CMessage 发送C2C消息（自动分配 msg_seq）
+func (p Processor) postC2CMessage(ctx context.Context, userID string, toCreate dto.APIMessage) error {
+	return postWithSeq(toCreate, func(msg dto.APIMessage) error {
+		_, err := p.api.PostC2CMessage(ctx, userID, msg)
+		return err
+	})
+}
+
 func (p Processor) sendGroupReply(ctx context.Context, groupID string, toCreate dto.APIMessage) error {
-	if _, err := p.api.PostGroupMessage(ctx, groupID, toCreate); err != nil {
+	if err := p.postGroupMessage(ctx, groupID, toCreate); err != nil {
 		botlog.Errorf("发送群消息失败: %v", err)
 		return err
 	}
 	return nil
 }
 
-func (p Processor) SendGroupFileByBase64(ctx context.Context, groupOpenID string, fileType int, fileData []byte, srvSendMsg bool) (*dto.MediaInfo, error) {
+// mediaUploadResult 富媒体上传接口的响应
+type mediaUploadResult struct {
+	dto.MediaInfo
+	FileUUID string `json:"file_uuid"`
+	TTL      int64  `json:"ttl"` // file_info 剩余有效期（秒），0 表示长期有效
+}
+
+func groupFilesURL(groupID string) string {
+	return fmt.Sprintf("%s/v2/groups/%s/files", constant.APIDomain, groupID)
+}
+
+func c2cFilesURL(userID string) string {
+	return fmt.Sprintf("%s/v2/users/%s/files", constant.APIDomain, userID)
+}
 
-	// 构建请求体
-	payload := ImgRichData{
-		Group_openid: groupOpenID,
-		File_type:    fileType,
-		File_data:    base64.StdEncoding.EncodeToString(fileData),
-		Srv_send_msg: srvSendMsg,
+// uploadGroupMedia 上传富媒体文件到群，返回可用于发送的 file_info
+func (p Processor) uploadGroupMedia(ctx context.Context, groupID string, body RichMediaUpload) (*dto.MediaInfo, error) {
+	res, err := p.uploadMedia(ctx, groupFilesURL(groupID), body)
+	if err != nil {
+		return nil, err
 	}
+	return &res.MediaInfo, nil
+}
 
-	media, err := p.UploadPicToGroup(ctx, groupOpenID, payload)
+// uploadC2CMedia 上传富媒体文件到单聊，返回可用于发送的 file_info
+func (p Processor) uploadC2CMedia(ctx context.Context, userID string, body RichMediaUpload) (*dto.MediaInfo, error) {
+	res, err := p.uploadMedia(ctx, c2cFilesURL(userID), body)
 	if err != nil {
-		botlog.Errorf("上传文件失败: %v", err)
 		return nil, err
 	}
+	return &res.MediaInfo, nil
+}
 
-	return media, err
+// uploadGroupData 上传本地数据到群（相同内容在有效期内复用已上传的 file_info）
+func (p Processor) uploadGroupData(ctx context.Context, groupID string, fileType int, data []byte) (*dto.MediaInfo, error) {
+	return p.uploadMediaData(ctx, groupFilesURL(groupID), fileType, data)
+}
+
+// uploadC2CData 上传本地数据到单聊（相同内容在有效期内复用已上传的 file_info）
+func (p Processor) uploadC2CData(ctx context.Context, userID string, fileType int, data []byte) (*dto.MediaInfo, error) {
+	return p.uploadMediaData(ctx, c2cFilesURL(userID), fileType, data)
+}
+
+func (p Processor) uploadMediaData(ctx context.Context, url string, fileType int, data []byte) (*dto.MediaInfo, error) {
+	key := mediaCacheKey{uploadURL: url, fileType: fileType, sum: sha256.Sum256(data)}
+	if media, ok := uploadedMedia.Get(key); ok {
+		return media, nil
+	}
+
+	res, err := p.uploadMedia(ctx, url, RichMediaUpload{
+		FileType: fileType,
+		FileData: base64.StdEncoding.EncodeToString(data),
+	})
+	if err != nil {
+		return nil, err
+	}
+	uploadedMedia.Put(key, &res.MediaInfo, res.TTL)
+	return &res.MediaInfo, nil
 }
 
-// base64上传图片到群
-func (p Processor) UploadPicToGroup(ctx context.Context, groupID string, body ImgRichData) (*dto.MediaInfo, error) {
-	url := fmt.Sprintf("https://api.sgroup.qq.com/v2/groups/%s/files", groupID)
+func (p Processor) uploadMedia(ctx context.Context, url string, body RichMediaUpload) (res *mediaUploadResult, err error) {
+	defer func() {
+		if err != nil {
+			uploadFailures.WithLabelValues(mediaFileTypeName(body.FileType)).Inc()
+		}
+	}()
+
 	fileInfo, err := p.api.Transport(ctx, "POST", url, body)
 	if err != nil {
-		botlog.Errorf("Transport request failed: %v", err)
+		botlog.Errorf("上传富媒体文件失败: %v", err)
 		return nil, err
 	}
 	if len(fileInfo) == 0 {
-		botlog.Errorf("UploadPicToGroup: received empty response body")
+		botlog.Errorf("上传富媒体文件失败: 响应为空")
 		return nil, fmt.Errorf("received empty response body")
 	}
 
-	var media dto.MediaInfo
-	err = json.Unmarshal(fileInfo, &media)
-	if err != nil {
+	res = &mediaUploadResult{}
+	if err := json.Unmarshal(fileInfo, res); err != nil {
 		botlog.Errorf("JSON 解析失败: %v", err)
 		return nil, err
 	}
-	return &media, nil
+	return res, nil
 }
 
-// 通过resty手动实现上传图片
-func (p Processor) SendPicToDirectMsg(ctx context.Context, group_openid string, data map[string]interface{}) (*dto.MediaInfo, error) {
-	tk, _ := p.token.Token()
-	payload, err := json.Marshal(data)
+// sendChannelImgDataReply 通过 multipart 表单向子频道发送本地图片（SDK 未提供该接口）
+func (p Processor) sendChannelImgDataReply(ctx context.Context, channelID string, msgID string, fileData []byte, content string) error {
+	tk, err := p.token.Token()
 	if err != nil {
-		botlog.Errorf("Failed to marshal payload: %v", err)
-		return nil, err
+		return fmt.Errorf("获取 Token 失败: %w", err)
+	}
+	form := map[string]string{"msg_id": msgID}
+	if content != "" {
+		form["content"] = content
 	}
 	resp, err := resty.New().R().
 		SetContext(ctx).
-		SetAuthScheme("Bot").
 		SetAuthScheme(tk.TokenType).
 		SetAuthToken(tk.AccessToken).
-		SetBody(payload).
-		SetContentLength(true).
-		SetResult(dto.MediaInfo{}).
-		SetPathParam("group_openid", group_openid).
-		Post(fmt.Sprintf("%s://%s%s", "https", "api.sgroup.qq.com", "/v2/groups/{group_openid}/files"))
+		SetMultipartFormData(form).
+		SetFileReader("file_image", "image.jpg", bytes.NewReader(fileData)).
+		SetPathParam("channel_id", channelID).
+		Post(fmt.Sprintf("%s/channels/{channel_id}/messages", constant.APIDomain))
 	if err != nil {
-		return nil, err
-	}
-	return resp.Result().(*dto.MediaInfo), nil
-}
-
-// 通过本地方式上传图片（Base64）
-func (p Processor) sendGroupImgDataReply(ctx context.Context, groupID string, fileData []byte, toSend dto.APIMessage) error {
-	// 构建请求体
-	payload := ImgRichData{
-		Group_openid: groupID,
-		File_type:    1,
-		File_data:    base64.StdEncoding.EncodeToString(fileData),
-		Srv_send_msg: false,
-	}
-	fileInfo, err := p.UploadPicToGroup(ctx, groupID, payload)
-	if err != nil {
-		botlog.Errorf("上传图片失败: %v", err)
+		botlog.Errorf("发送频道图片失败: %v", err)
 		return err
 	}
-
-	toSend.(*dto.MessageToCreate).Media = fileInfo
-	toSend.(*dto.MessageToCreate).Timestamp = time.Now().UnixMilli()
-	_, err = p.api.PostGroupMessage(ctx, groupID, toSend)
-	if err != nil {
-		if strings.Contains(err.Error(), "消息被去重，请检查请求msgseq") {
-			return nil
-		}
-		return err
-	}
-	return nil
-}
-
-// 通过URL方式发送图片
-func (p Processor) sendGroupImgReply(ctx context.Context, groupID string, toCreate dto.APIMessage, toSend dto.APIMessage) error {
-	fileInfo, err := p.api.PostGroupMessage(ctx, groupID, toCreate)
-	if err != nil {
-		botlog.Errorf("发送图片消息失败: %v", err)
-		return err
-	}
-	toSend.(*dto.MessageToCreate).Media.FileInfo = fileInfo.FileInfo
-	_, err = p.api.PostGroupMessage(ctx, groupID, toSend)
-	if err != nil {
-		botlog.Errorf("发送图片消息失败: %v", err)
-		return err
+	if resp.IsError() {
+		botlog.Errorf("发送频道图片失败: 状态码%d, %s", resp.StatusCode(), resp.String())
+		return fmt.Errorf("发送频道图片失败: 状态码%d", resp.StatusCode())
 	}
 	return nil
 }
 
 func (p Processor) sendC2CReply(ctx context.Context, userID string, toCreate dto.APIMessage) error {
-	if _, err := p.api.PostC2CMessage(ctx, userID, toCreate); err != nil {
-		if strings.Contains(err.Error(), "消息被去重，请检查请求msgseq") {
-			return nil
-		}
+	if err := p.postC2CMessage(ctx, userID, toCreate); err != nil {
 		botlog.Errorf("发送C2C消息失败: %v", err)
 		return err
 	}
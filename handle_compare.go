@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/newton-miku/apexQQbot/apexapi"
+	botlog "github.com/tencent-connect/botgo/log"
+)
+
+const compareUsage = "格式为 /a对比 <EAID1> <EAID2>，或 /a对比 <EAID> 与自己绑定的账号对比；\n" +
+	"群聊中可用 @群成员 代替 EAID（该成员需已绑定），如 /a对比 @群友 或 /a对比 @群友A @群友B"
+
+// 消息内容中的@标记，如 <@member_openid>
+var mentionRE = regexp.MustCompile(`^<@!?([^>]+)>$`)
+
+// handleCompare 对比两名玩家的数据并回复对比卡片
+func handleCompare(ctx context.Context, env cmdEnv, args []string) error {
+	eaids, msg := resolveCompareTargets(env, args)
+	if msg != "" {
+		return env.r.Text(ctx, msg)
+	}
+
+	var eaid1, eaid2 string
+	switch len(eaids) {
+	case 1:
+		if env.user == nil {
+			return env.r.Text(ctx, compareUsage)
+		}
+		binding, ok := apexapi.Players.Get(env.user.ID)
+		if !ok {
+			return env.r.Text(ctx, "您尚未绑定 EAID，请使用 /a绑定 <EAID> 进行绑定，或提供两个 EAID\n"+compareUsage)
+		}
+		eaid1, eaid2 = binding.EAID, eaids[0]
+	case 2:
+		eaid1, eaid2 = eaids[0], eaids[1]
+	default:
+		return env.r.Text(ctx, compareUsage)
+	}
+
+	a, b, err := apexapi.FetchPlayersConcurrently(ctx, eaid1, eaid2)
+	if err != nil {
+		return replyError(ctx, env.r, err)
+	}
+	img, err := apexapi.RenderCompareImage(a, b)
+	if err != nil {
+		return replyError(ctx, env.r, err)
+	}
+	if err := env.r.Image(ctx, img, ""); err != nil {
+		botlog.Errorf("发送对比图片失败: %v", err)
+	}
+	return nil
+}
+
+// resolveCompareTargets 将参数中@的群成员替换为其绑定的 EAID；无法解析时返回提示信息
+func resolveCompareTargets(env cmdEnv, args []string) ([]string, string) {
+	var memberIDs, eaids []string
+	for _, arg := range args {
+		if m := mentionRE.FindStringSubmatch(arg); m != nil {
+			memberIDs = append(memberIDs, m[1])
+			continue
+		}
+		eaids = append(eaids, arg)
+	}
+	// 平台提供了 mentions 时以其为准（保留 openid 原始大小写），否则使用内容中的@标记
+	if len(env.mentions) > 0 {
+		memberIDs = memberIDs[:0]
+		for _, u := range env.mentions {
+			memberIDs = append(memberIDs, u.ID)
+		}
+	}
+	if len(memberIDs) == 0 {
+		return eaids, ""
+	}
+	if env.groupID == "" {
+		return nil, compareUsage
+	}
+
+	var members []string
+	if recorded, err := apexapi.GroupSettings.GroupMembers(env.groupID); err != nil {
+		botlog.Warnf("读取群成员失败: %v", err)
+	} else {
+		members = recorded
+	}
+
+	resolved := make([]string, 0, len(memberIDs)+len(eaids))
+	for _, id := range memberIDs {
+		binding, ok := apexapi.Players.Get(resolveMemberID(members, id))
+		if !ok {
+			return nil, "被@的成员尚未绑定 EAID，请对方先使用 /a绑定 <EAID> 进行绑定\n" + compareUsage
+		}
+		resolved = append(resolved, binding.EAID)
+	}
+	return append(resolved, eaids...), ""
+}
+
+// resolveMemberID 在本群记录的成员中查找 id（指令内容已转为小写，需忽略大小写匹配）
+func resolveMemberID(members []string, id string) string {
+	if i := slices.IndexFunc(members, func(m string) bool { return strings.EqualFold(m, id) }); i >= 0 {
+		return members[i]
+	}
+	return id
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/newton-miku/apexQQbot/apexapi"
+)
+
+var lfgListArgs = []string{"列表", "list"}
+
+// mentionUser 群消息中 @ 指定成员的写法
+func mentionUser(openID string) string {
+	return fmt.Sprintf(`<qqbot-at-user id="%s" />`, openID)
+}
+
+// requireLFGBinding 组队需要在群内并已绑定 EAID，以便展示段位
+func requireLFGBinding(ctx context.Context, env cmdEnv) (apexapi.PlayerBindingData, bool) {
+	if env.groupID == "" {
+		_ = env.r.Text(ctx, "组队仅支持在群内使用")
+		return apexapi.PlayerBindingData{}, false
+	}
+	if env.user == nil {
+		return apexapi.PlayerBindingData{}, false
+	}
+	binding, ok := apexapi.Players.Get(env.user.ID)
+	if !ok {
+		_ = env.r.Text(ctx, "组队前请先使用 /a绑定 <EAID> 绑定账号")
+		return apexapi.PlayerBindingData{}, false
+	}
+	binding.QQ = env.user.ID
+	return binding, true
+}
+
+// handleLFGCreate 发布组队：/a组队 [模式] [段位]；/a组队 列表 查看本群正在招募的队伍
+func handleLFGCreate(ctx context.Context, env cmdEnv, args []string) error {
+	if len(args) > 0 && slices.Contains(lfgListArgs, strings.ToLower(args[0])) {
+		return handleLFGList(ctx, env)
+	}
+
+	binding, ok := requireLFGBinding(ctx, env)
+	if !ok {
+		return nil
+	}
+
+	var mode string
+	if len(args) > 0 {
+		if m, ok := apexapi.ParseMapMode(args[0]); ok {
+			mode = m
+			args = args[1:]
+		}
+	}
+	rank := strings.Join(args, " ")
+
+	entry, err := apexapi.LFG.Create(env.groupID, mode, rank, binding, apexapi.LFGDefaultTTL)
+	if err != nil {
+		return replyError(ctx, env.r, err)
+	}
+	return env.r.Text(ctx, apexapi.FormatLFGEntry(entry))
+}
+
+// handleLFGList 列出本群正在招募的队伍
+func handleLFGList(ctx context.Context, env cmdEnv) error {
+	if env.groupID == "" {
+		return env.r.Text(ctx, "组队仅支持在群内使用")
+	}
+	entries, err := apexapi.LFG.Open(env.groupID)
+	if err != nil {
+		return replyError(ctx, env.r, err)
+	}
+	if len(entries) == 0 {
+		return env.r.Text(ctx, "本群暂无正在招募的队伍，使用 /a组队 [模式] [段位] 发起组队")
+	}
+	parts := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		parts = append(parts, apexapi.FormatLFGEntry(entry))
+	}
+	return env.r.Text(ctx, strings.Join(parts, "\n\n"))
+}
+
+// handleLFGJoin 加入组队：/a加入 [编号]，不带编号时加入最新的队伍；满员后 @ 全部成员
+func handleLFGJoin(ctx context.Context, env cmdEnv, args []string) error {
+	binding, ok := requireLFGBinding(ctx, env)
+	if !ok {
+		return nil
+	}
+
+	var entryID int64
+	if len(args) > 0 {
+		id, err := strconv.ParseInt(strings.TrimPrefix(args[0], "#"), 10, 64)
+		if err != nil || id <= 0 {
+			return env.r.Text(ctx, "格式为 /a加入 [编号]，如 /a加入 12")
+		}
+		entryID = id
+	}
+
+	entry, err := apexapi.LFG.Join(env.groupID, entryID, binding)
+	switch {
+	case errors.Is(err, apexapi.ErrLFGNotFound), errors.Is(err, apexapi.ErrLFGAlreadyJoined):
+		return env.r.Text(ctx, err.Error())
+	case err != nil:
+		return replyError(ctx, env.r, err)
+	}
+
+	msg := apexapi.FormatLFGEntry(entry)
+	if entry.Full() {
+		mentions := make([]string, 0, len(entry.Members))
+		for _, m := range entry.Members {
+			mentions = append(mentions, mentionUser(m.QQ))
+		}
+		msg = fmt.Sprintf("%s\n人齐了，开黑！%s", msg, strings.Join(mentions, " "))
+	}
+	return env.r.Text(ctx, msg)
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/newton-miku/apexQQbot/apexapi"
+	botlog "github.com/tencent-connect/botgo/log"
+)
+
+var (
+	mapModeCmds     = []string{"模式", "mode"}
+	mapModeResetArg = []string{"全部", "all", "重置", "reset"}
+	mapScheduleCmds = []string{"时间表", "schedule"}
+)
+
+// 时间表展示的时长
+const mapScheduleWindow = 12 * time.Hour
+
+// 用户输入与展示的时刻均使用北京时间，不受服务器时区影响（中国不使用夏令时，固定为 UTC+8）
+var clockLocation = time.FixedZone("Asia/Shanghai", 8*60*60)
+
+// 时刻参数，如 21:00、21：30、21点、21点30
+var clockPattern = regexp.MustCompile(`^(\d{1,2})(?:[:：](\d{2})|点(\d{1,2})?分?)$`)
+
+// handleMapCommand 处理地图指令：无参数时发送轮换图片，其余为子指令
+func handleMapCommand(ctx context.Context, env cmdEnv, args []string) error {
+	if len(args) > 0 && isCommandMatch(args[0], mapModeCmds) {
+		return handleMapModes(ctx, env, args[1:])
+	}
+	if len(args) > 0 && isCommandMatch(args[0], mapScheduleCmds) {
+		return handleMapSchedule(ctx, env)
+	}
+	if len(args) > 0 {
+		if at, ok := parseClock(args[0], time.Now()); ok {
+			return handleMapAt(ctx, env, at)
+		}
+	}
+
+	modes := apexapi.GroupSettings.GetMapModes(env.settingScope())
+	mapImg, err := apexapi.GetMapResultImage(modes...)
+	if err != nil {
+		return replyError(ctx, env.r, err)
+	}
+	if err := env.r.Image(ctx, mapImg, ""); err != nil {
+		botlog.Errorf("发送地图图片失败: %v", err)
+	}
+	return nil
+}
+
+// handleMapModes 查看或设置地图图片中展示的模式
+func handleMapModes(ctx context.Context, env cmdEnv, args []string) error {
+	scope := env.settingScope()
+
+	if len(args) == 0 {
+		return env.r.Text(ctx, mapModesMessage(apexapi.GroupSettings.GetMapModes(scope)))
+	}
+
+	if len(args) == 1 && isCommandMatch(args[0], mapModeResetArg) {
+		if err := apexapi.GroupSettings.SetMapModes(scope, nil); err != nil {
+			return replyError(ctx, env.r, err)
+		}
+		return env.r.Text(ctx, "已恢复展示全部模式")
+	}
+
+	modes := make([]string, 0, len(args))
+	var unknown []string
+	for _, arg := range args {
+		mode, ok := apexapi.ParseMapMode(arg)
+		if !ok {
+			unknown = append(unknown, arg)
+			continue
+		}
+		if !slices.Contains(modes, mode) {
+			modes = append(modes, mode)
+		}
+	}
+	if len(unknown) > 0 {
+		return env.r.Text(ctx, fmt.Sprintf("无法识别的模式：%s\n%s", strings.Join(unknown, "、"), mapModesMessage(nil)))
+	}
+
+	if err := apexapi.GroupSettings.SetMapModes(scope, modes); err != nil {
+		return replyError(ctx, env.r, err)
+	}
+	return env.r.Text(ctx, fmt.Sprintf("地图轮换将展示：%s", joinModeNames(modes)))
+}
+
+// mapModesMessage 生成当前模式设置与可选模式的说明
+func mapModesMessage(selected []string) string {
+	var b strings.Builder
+	if len(selected) == 0 {
+		b.WriteString("当前展示：全部模式\n")
+	} else {
+		b.WriteString(fmt.Sprintf("当前展示：%s\n", joinModeNames(selected)))
+	}
+	if mapRotate, err := apexapi.GetMapRotate(); err == nil {
+		b.WriteString(fmt.Sprintf("当前可选：%s\n", joinModeNames(mapRotate.Modes())))
+	}
+	b.WriteString("设置方式：/a地图 模式 <模式...>，恢复全部：/a地图 模式 全部")
+	return b.String()
+}
+
+func joinModeNames(modes []string) string {
+	names := make([]string, 0, len(modes))
+	for _, mode := range modes {
+		names = append(names, apexapi.GetModeName(mode))
+	}
+	return strings.Join(names, "、")
+}
+
+// scheduleModes 时间表展示的模式：群设置的模式，未设置时为当前全部模式
+func scheduleModes(env cmdEnv) []string {
+	if modes := apexapi.GroupSettings.GetMapModes(env.settingScope()); len(modes) > 0 {
+		return modes
+	}
+	mapRotate, err := apexapi.GetMapRotate()
+	if err != nil {
+		return []string{"battle_royale", "ranked"}
+	}
+	return mapRotate.Modes()
+}
+
+// handleMapSchedule 回复未来一段时间的轮换时间表
+func handleMapSchedule(ctx context.Context, env cmdEnv) error {
+	now := time.Now()
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("地图轮换时间表（未来%d小时）：\n", int(mapScheduleWindow.Hours())))
+	for _, mode := range scheduleModes(env) {
+		schedule, err := apexapi.PredictMapSchedule(mode, now, now.Add(mapScheduleWindow))
+		if err != nil {
+			return replyError(ctx, env.r, err)
+		}
+		b.WriteString(fmt.Sprintf("【%s】\n", apexapi.GetModeName(mode)))
+		if len(schedule) == 0 {
+			b.WriteString("  暂无数据\n")
+			continue
+		}
+		for _, slot := range schedule {
+			b.WriteString(fmt.Sprintf("  %s-%s %s（%s）\n",
+				formatClock(slot.Start, now), formatClock(slot.End, now), apexapi.GetMapName(slot.Code), slot.Confidence))
+		}
+	}
+	b.WriteString("预测基于已记录的轮换周期，仅供参考")
+	return env.r.Text(ctx, b.String())
+}
+
+// handleMapAt 回复指定时刻的轮换
+func handleMapAt(ctx context.Context, env cmdEnv, at time.Time) error {
+	now := time.Now()
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("%s 的地图轮换：\n", formatClock(at, now)))
+	for _, mode := range scheduleModes(env) {
+		slot, ok, err := apexapi.PredictMapAt(mode, at)
+		if err != nil {
+			return replyError(ctx, env.r, err)
+		}
+		if !ok {
+			b.WriteString(fmt.Sprintf("%s：暂无足够数据推算\n", apexapi.GetModeName(mode)))
+			continue
+		}
+		b.WriteString(fmt.Sprintf("%s：%s（%s-%s，%s）\n", apexapi.GetModeName(mode), apexapi.GetMapName(slot.Code),
+			formatClock(slot.Start, now), formatClock(slot.End, now), slot.Confidence))
+	}
+	return env.r.Text(ctx, strings.TrimRight(b.String(), "\n"))
+}
+
+// parseClock 按北京时间解析时刻参数，若该时刻今天已过则视为明天
+func parseClock(s string, now time.Time) (time.Time, bool) {
+	now = now.In(clockLocation)
+	m := clockPattern.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return time.Time{}, false
+	}
+	hour, _ := strconv.Atoi(m[1])
+	minute := 0
+	for _, v := range m[2:] {
+		if v != "" {
+			minute, _ = strconv.Atoi(v)
+		}
+	}
+	if hour > 23 || minute > 59 {
+		return time.Time{}, false
+	}
+	at := time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, now.Location())
+	if at.Before(now) {
+		at = at.AddDate(0, 0, 1)
+	}
+	return at, true
+}
+
+// formatClock 按北京时间格式化时刻，不在今天时带上日期
+func formatClock(t, now time.Time) string {
+	t, now = t.In(clockLocation), now.In(clockLocation)
+	if t.YearDay() == now.YearDay() && t.Year() == now.Year() {
+		return t.Format("15:04")
+	}
+	return t.Format("01-02 15:04")
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/newton-miku/apexQQbot/apexapi"
+	"github.com/tencent-connect/botgo/dto"
+	botlog "github.com/tencent-connect/botgo/log"
+)
+
+var (
+	newsSubscribeArgs   = []string{"订阅", "subscribe"}
+	newsUnsubscribeArgs = []string{"取消订阅", "unsubscribe"}
+)
+
+// 指令查看新闻时展示的条数
+const newsListSize = 3
+
+// handleNewsCommand 查看最新新闻，或订阅/取消订阅本群的新闻推送
+func handleNewsCommand(ctx context.Context, env cmdEnv, args []string) error {
+	if len(args) > 0 {
+		var subscribed bool
+		switch {
+		case isCommandMatch(args[0], newsUnsubscribeArgs):
+			subscribed = false
+		case isCommandMatch(args[0], newsSubscribeArgs):
+			subscribed = true
+		default:
+			return env.r.Text(ctx, "格式为 /a新闻 [订阅|取消订阅]")
+		}
+		if env.groupID == "" {
+			return env.r.Text(ctx, "新闻推送仅支持在群内订阅")
+		}
+		if err := apexapi.GroupSettings.SetNewsSubscribed(env.groupID, subscribed); err != nil {
+			return replyError(ctx, env.r, err)
+		}
+		if subscribed {
+			return env.r.Text(ctx, "已订阅新闻推送，有新的新闻或更新公告时将在群内通知")
+		}
+		return env.r.Text(ctx, "已取消订阅新闻推送")
+	}
+
+	items, err := apexapi.FetchNews(ctx)
+	if err != nil {
+		return replyError(ctx, env.r, err)
+	}
+	if len(items) == 0 {
+		return env.r.Text(ctx, "暂无新闻")
+	}
+	if len(items) > newsListSize {
+		items = items[:newsListSize]
+	}
+
+	var b strings.Builder
+	for i, item := range items {
+		if i > 0 {
+			b.WriteString("\n\n")
+		}
+		b.WriteString(apexapi.FormatNewsItem(item))
+	}
+	if env.groupID != "" && !apexapi.GroupSettings.NewsSubscribed(env.groupID) {
+		b.WriteString("\n\n使用 /a新闻 订阅 在本群接收新闻推送")
+	}
+	return env.r.Text(ctx, b.String())
+}
+
+// pushNews 将新闻推送到订阅的群，有配图时以图文消息发送，图片上传失败则仅发送文字
+func (p Processor) pushNews(ctx context.Context, items []apexapi.NewsItem, groups []string) {
+	for _, groupID := range groups {
+		for _, item := range items {
+			if err := p.pushNewsItem(ctx, groupID, item); err != nil {
+				botlog.Warnf("推送新闻到群 %s 失败: %v", groupID, err)
+			}
+		}
+	}
+}
+
+func (p Processor) pushNewsItem(ctx context.Context, groupID string, item apexapi.NewsItem) error {
+	content := apexapi.FormatNewsItem(item)
+	if item.Image != "" {
+		media, err := p.uploadGroupMedia(ctx, groupID, RichMediaUpload{FileType: fileTypeImage, URL: item.Image})
+		if err == nil {
+			msg := createRichMessage(dto.Message{}, content)
+			msg.Media = media
+			if err = p.sendGroupReply(ctx, groupID, msg); err == nil {
+				return nil
+			}
+		}
+		botlog.Warnf("发送新闻图片 %s 失败，改为发送文字: %v", item.Image, err)
+	}
+	return p.sendGroupReply(ctx, groupID, &dto.MessageToCreate{
+		Timestamp: time.Now().UnixMilli(),
+		Content:   content,
+	})
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/newton-miku/apexQQbot/apexapi"
+)
+
+var onlineGroupArgs = []string{"群", "group", "全部", "all"}
+
+// handleOnline 查询玩家在线状态；参数为“群”时列出本群已绑定成员的在线情况
+func handleOnline(ctx context.Context, env cmdEnv, args []string) error {
+	if len(args) > 0 && slices.Contains(onlineGroupArgs, strings.ToLower(args[0])) {
+		if env.groupID == "" {
+			return env.r.Text(ctx, "群成员在线情况仅支持在群内查询")
+		}
+		statuses, err := apexapi.GetGroupOnlineStatus(ctx, env.groupID)
+		if err != nil {
+			return replyError(ctx, env.r, err)
+		}
+		return env.r.Text(ctx, apexapi.FormatGroupOnlineStatus(statuses))
+	}
+
+	var eaid string
+	if len(args) > 0 {
+		eaid = args[0]
+	} else if env.user != nil {
+		eaid, _ = apexapi.Players.GetEAIDbyQQ(env.user.ID)
+	}
+	if eaid == "" {
+		return env.r.Text(ctx, "您尚未绑定 EAID，请使用 /a绑定 <EAID> 进行绑定，或使用 /a在线 <EAID> 查询")
+	}
+
+	player, err := apexapi.GetPlayerData(ctx, eaid)
+	if err != nil {
+		return replyError(ctx, env.r, err)
+	}
+	return env.r.Text(ctx, apexapi.FormatRealtimeStatus(player))
+}
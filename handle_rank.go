@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/newton-miku/apexQQbot/apexapi"
+	"github.com/tencent-connect/botgo/dto"
+	botlog "github.com/tencent-connect/botgo/log"
+)
+
+var (
+	announceOnArgs  = []string{"开启", "on"}
+	announceOffArgs = []string{"关闭", "off"}
+)
+
+// handleAnnounceCommand 查看或设置本群的段位变化播报
+func handleAnnounceCommand(ctx context.Context, env cmdEnv, args []string) error {
+	if env.groupID == "" {
+		return env.r.Text(ctx, "段位播报仅支持在群内设置")
+	}
+
+	if len(args) == 0 {
+		status := "关闭"
+		if apexapi.GroupSettings.RankAnnounceEnabled(env.groupID) {
+			status = "开启"
+		}
+		msg := fmt.Sprintf("本群段位播报：%s\n使用 /a播报 开启|关闭 进行设置", status)
+		if _, ok := apexapi.GetPollInterval(); !ok {
+			msg += "\n（机器人未启用后台轮询，播报暂不会生效）"
+		}
+		return env.r.Text(ctx, msg)
+	}
+
+	var enabled bool
+	switch {
+	case isCommandMatch(args[0], announceOnArgs):
+		enabled = true
+	case isCommandMatch(args[0], announceOffArgs):
+		enabled = false
+	default:
+		return env.r.Text(ctx, "格式为 /a播报 开启|关闭")
+	}
+
+	if err := apexapi.GroupSettings.SetRankAnnounce(env.groupID, enabled); err != nil {
+		return replyError(ctx, env.r, err)
+	}
+	if enabled {
+		return env.r.Text(ctx, "已开启段位播报，本群已绑定的成员晋级或分数大幅变化时将在群内通知")
+	}
+	return env.r.Text(ctx, "已关闭段位播报")
+}
+
+// rememberGroupMember 记录已绑定成员所在的群，作为段位播报的目标
+func rememberGroupMember(env cmdEnv) {
+	if env.groupID == "" || env.user == nil {
+		return
+	}
+	if err := apexapi.GroupSettings.RecordMember(env.groupID, env.user.ID); err != nil {
+		botlog.Warnf("记录群成员失败: %v", err)
+	}
+}
+
+// formatRankAnnouncement 生成段位变化的播报内容
+func formatRankAnnouncement(change apexapi.RankChange) string {
+	msg := fmt.Sprintf("【段位播报】%s 的段位分数 %d → %d（%+d）\n", change.EAID, change.LastScore, change.Score, change.Delta())
+	if change.Notice != "" {
+		msg += change.Notice
+	}
+	return msg + apexapi.FormatRankProgress(change.Score)
+}
+
+// announceRankChange 将段位变化推送到开启了播报的群
+func (p Processor) announceRankChange(ctx context.Context, change apexapi.RankChange) {
+	content := formatRankAnnouncement(change)
+	for _, groupID := range change.Groups {
+		msg := &dto.MessageToCreate{
+			Timestamp: time.Now().UnixMilli(),
+			Content:   content,
+		}
+		if err := p.sendGroupReply(ctx, groupID, msg); err != nil {
+			botlog.Warnf("推送段位播报到群 %s 失败: %v", groupID, err)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/newton-miku/apexQQbot/apexapi"
+	"github.com/newton-miku/apexQQbot/tools"
+	botlog "github.com/tencent-connect/botgo/log"
+	"golang.org/x/oauth2"
+)
+
+// 地图轮换超过该时间未成功获取时视为未就绪（后台渲染在每次轮换结束时都会重新获取）
+const mapFetchMaxAge = 3 * time.Hour
+
+var startTime = time.Now()
+
+// trackedTokenSource 记录后台刷新得到的最新 Token，供就绪检查使用
+type trackedTokenSource struct {
+	oauth2.TokenSource
+
+	mu   sync.RWMutex
+	last *oauth2.Token
+	err  error
+}
+
+func (t *trackedTokenSource) Token() (*oauth2.Token, error) {
+	tk, err := t.TokenSource.Token()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err == nil {
+		t.last = tk
+	}
+	t.err = err
+	return tk, err
+}
+
+// current 返回最近获取到的 Token 与最近一次获取的错误，不会触发新的请求
+func (t *trackedTokenSource) current() (*oauth2.Token, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.last, t.err
+}
+
+// healthCheck 单项检查结果
+type healthCheck struct {
+	Name   string `json:"name"`
+	Status string `json:"status"` // ok 或 fail
+	Detail string `json:"detail,omitempty"`
+}
+
+// healthReport /healthz 与 /readyz 的响应
+type healthReport struct {
+	Status  string        `json:"status"`
+	Version string        `json:"version"`
+	Uptime  string        `json:"uptime"`
+	Checks  []healthCheck `json:"checks,omitempty"`
+}
+
+func newHealthReport() healthReport {
+	return healthReport{
+		Status:  "ok",
+		Version: tools.Version,
+		Uptime:  time.Since(startTime).Round(time.Second).String(),
+	}
+}
+
+// add 添加一项检查结果，任一项失败时整体为 fail。
+// 接口无需鉴权，失败时响应中只包含 detail（为空时使用错误类别），完整错误只记录到日志
+func (r *healthReport) add(name string, detail string, err error) {
+	check := healthCheck{Name: name, Status: "ok", Detail: detail}
+	if err != nil {
+		botlog.Warnf("就绪检查 %s 失败: %v", name, err)
+		check.Status = "fail"
+		if detail == "" {
+			check.Detail = apexapi.ErrorKindOf(err).String()
+		}
+		r.Status = "fail"
+	}
+	r.Checks = append(r.Checks, check)
+}
+
+func writeHealthReport(writer http.ResponseWriter, report healthReport) {
+	writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+	writer.Header().Set("Cache-Control", "no-store")
+	if report.Status != "ok" {
+		writer.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.NewEncoder(writer).Encode(report)
+}
+
+// healthzHandler 存活检查：进程能响应请求即视为存活
+func healthzHandler(writer http.ResponseWriter, request *http.Request) {
+	writeHealthReport(writer, newHealthReport())
+}
+
+// readyzHandler 就绪检查：数据库、Token、资源目录与地图数据均可用时才就绪
+func readyzHandler(tokens *trackedTokenSource) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		ctx, cancel := context.WithTimeout(request.Context(), 3*time.Second)
+		defer cancel()
+
+		report := newHealthReport()
+		report.add("sqlite", "", apexapi.Players.Ping(ctx))
+		report.add(checkToken(tokens))
+		report.add(checkAssetDir())
+		report.add(checkMapFetch(time.Now()))
+		writeHealthReport(writer, report)
+	}
+}
+
+// 以下各项检查失败时返回的 detail 会写入响应，不能包含错误原文
+
+func checkToken(tokens *trackedTokenSource) (string, string, error) {
+	tk, err := tokens.current()
+	switch {
+	case tk == nil && err != nil:
+		return "token", "获取 Token 失败", err
+	case tk == nil:
+		return "token", "尚未获取 Token", errors.New("尚未获取 Token")
+	case !tk.Valid():
+		if err != nil {
+			return "token", "Token 已过期，刷新失败", err
+		}
+		detail := fmt.Sprintf("Token 已过期（%s）", tk.Expiry.Format(time.DateTime))
+		return "token", detail, errors.New(detail)
+	}
+	return "token", "有效期至 " + tk.Expiry.Format(time.DateTime), nil
+}
+
+func checkAssetDir() (string, string, error) {
+	dir, err := apexapi.GetAssetPath()
+	if err != nil {
+		return "asset", "无法获取资源目录", err
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return "asset", "资源目录不可用", err
+	}
+	if !info.IsDir() {
+		return "asset", "资源路径不是目录", fmt.Errorf("%s 不是目录", dir)
+	}
+	return "asset", dir, nil
+}
+
+func checkMapFetch(now time.Time) (string, string, error) {
+	at, err := apexapi.LastMapFetch()
+	switch {
+	case at.IsZero() && err != nil:
+		return "map", "尚未成功获取地图轮换（" + apexapi.ErrorKindOf(err).String() + "）", err
+	case at.IsZero():
+		return "map", "尚未获取地图轮换", errors.New("尚未获取地图轮换")
+	case now.Sub(at) > mapFetchMaxAge:
+		detail := fmt.Sprintf("地图轮换已 %s 未更新", now.Sub(at).Round(time.Minute))
+		if err != nil {
+			return "map", detail + "（" + apexapi.ErrorKindOf(err).String() + "）", err
+		}
+		return "map", detail, errors.New(detail)
+	}
+	detail := "最近获取于 " + at.Format(time.DateTime)
+	if err != nil {
+		// 偶尔的获取失败不影响就绪状态，原文已在获取时记录到日志
+		detail += "，最近一次获取失败（" + apexapi.ErrorKindOf(err).String() + "）"
+	}
+	return "map", detail, nil
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/newton-miku/apexQQbot/apexapi"
 	"github.com/newton-miku/apexQQbot/tools"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/tencent-connect/botgo"
 	"github.com/tencent-connect/botgo/dto"
 	"github.com/tencent-connect/botgo/dto/message"
@@ -77,7 +78,8 @@ func main() {
 		AppSecret: config.AppSecret,
 	}
 
-	tokenSource := token.NewQQBotTokenSource(credentials)
+	// 包装 token source，记录最新 Token 供就绪检查使用
+	tokenSource := &trackedTokenSource{TokenSource: token.NewQQBotTokenSource(credentials)}
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
@@ -85,6 +87,9 @@ func main() {
 		logger.Fatalf("刷新 Token 失败: %v", err)
 	}
 
+	// 后台预渲染地图轮换图片
+	apexapi.StartMapRenderer(ctx)
+
 	logger.Info("准备初始化 openapi")
 	api := botgo.NewOpenAPI(credentials.AppID, tokenSource).WithTimeout(5 * time.Second).SetDebug(DebugFlag)
 	processor = Processor{
@@ -94,6 +99,14 @@ func main() {
 		token:     tokenSource,
 	}
 
+	// 后台轮询已绑定玩家的段位（需在配置中设置 poll_interval）
+	if apexapi.StartRankPoller(ctx, processor.announceRankChange) {
+		logger.Info("已启动段位轮询")
+	}
+
+	// 后台检查新闻，推送到订阅的群
+	apexapi.StartNewsPoller(ctx, processor.pushNews)
+
 	// 注册处理函数
 	_ = event.RegisterHandlers(
 		GroupATMessageEventHandler(),
@@ -104,6 +117,11 @@ func main() {
 	http.HandleFunc(path_, func(writer http.ResponseWriter, request *http.Request) {
 		webhook.HTTPHandler(writer, request, credentials)
 	})
+	// 运行指标（Prometheus 文本格式）
+	http.Handle("/metrics", promhttp.Handler())
+	// 存活与就绪检查
+	http.HandleFunc("/healthz", healthzHandler)
+	http.HandleFunc("/readyz", readyzHandler(tokenSource))
 
 	logger.Info("准备启动 http server")
 	if err := http.ListenAndServe(fmt.Sprintf("%s:%d", host_, port_), nil); err != nil {
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"sync"
+	"time"
+
+	"github.com/tencent-connect/botgo/dto"
+)
+
+const (
+	// 平台返回 ttl=0 表示 file_info 可长期使用，此时仍设一个上限以便定期刷新
+	mediaCacheMaxTTL = 24 * time.Hour
+	// 提前失效的余量，避免发送时 file_info 恰好过期
+	mediaCacheTTLMargin = time.Minute
+)
+
+// mediaCacheKey 以上传地址（包含目标群/用户）、文件类型与内容哈希作为缓存键
+type mediaCacheKey struct {
+	uploadURL string
+	fileType  int
+	sum       [sha256.Size]byte
+}
+
+type mediaCacheEntry struct {
+	media     *dto.MediaInfo
+	expiresAt time.Time
+}
+
+// MediaCache 已上传富媒体的 file_info 缓存，相同内容发往同一目标时可直接复用
+type MediaCache struct {
+	mu    sync.Mutex
+	items map[mediaCacheKey]mediaCacheEntry
+}
+
+// NewMediaCache 创建富媒体缓存
+func NewMediaCache() *MediaCache {
+	return &MediaCache{items: make(map[mediaCacheKey]mediaCacheEntry)}
+}
+
+// 全局富媒体缓存
+var uploadedMedia = NewMediaCache()
+
+// Get 获取未过期的缓存
+func (c *MediaCache) Get(key mediaCacheKey) (*dto.MediaInfo, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(c.items, key)
+		return nil, false
+	}
+	return entry.media, true
+}
+
+// Put 写入缓存，ttl 为平台返回的有效期（秒）
+func (c *MediaCache) Put(key mediaCacheKey, media *dto.MediaInfo, ttl int64) {
+	lifetime := mediaCacheMaxTTL
+	if ttl > 0 {
+		lifetime = min(time.Duration(ttl)*time.Second, mediaCacheMaxTTL)
+	}
+	lifetime -= mediaCacheTTLMargin
+	if lifetime <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for k, entry := range c.items {
+		if now.After(entry.expiresAt) {
+			delete(c.items, k)
+		}
+	}
+	c.items[key] = mediaCacheEntry{media: media, expiresAt: now.Add(lifetime)}
+}
+
+// Remove 移除使用该 file_info 的缓存（发送失败时调用，下次将重新上传）
+func (c *MediaCache) Remove(media *dto.MediaInfo) {
+	if media == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for k, entry := range c.items {
+		if bytes.Equal(entry.media.FileInfo, media.FileInfo) {
+			delete(c.items, k)
+		}
+	}
+}
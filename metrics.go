@@ -0,0 +1,48 @@
+package main
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	commandInvocations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "apexbot_commands_total",
+		Help: "指令调用次数，scope 为 group 或 c2c",
+	}, []string{"command", "scope"})
+	uploadFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "apexbot_media_upload_failures_total",
+		Help: "富媒体上传失败次数",
+	}, []string{"file_type"})
+	dedupMessages = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "apexbot_messages_deduplicated_total",
+		Help: "因消息序号重复被平台去重的消息数",
+	})
+)
+
+// recordCommand 记录一次指令调用，command 使用英文指令名
+func recordCommand(env cmdEnv, command string) {
+	scope := "c2c"
+	if env.groupID != "" {
+		scope = "group"
+	}
+	commandInvocations.WithLabelValues(command, scope).Inc()
+}
+
+// mediaFileTypeName 富媒体类型的名称，用于指标标签
+func mediaFileTypeName(fileType int) string {
+	switch fileType {
+	case fileTypeImage:
+		return "image"
+	case fileTypeVideo:
+		return "video"
+	case fileTypeVoice:
+		return "voice"
+	case fileTypeFile:
+		return "file"
+	default:
+		return strconv.Itoa(fileType)
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/tencent-connect/botgo/dto"
+	"github.com/tencent-connect/botgo/errs"
+)
+
+const (
+	// 被动回复的有效期为 5 分钟，超过后对应 MsgID 的序号记录即可回收
+	msgSeqTTL = 5 * time.Minute
+	// 平台返回的“消息被去重，请检查请求msgseq”业务错误码
+	codeMsgSeqDuplicated = 40054005
+)
+
+// msgSeqEntry 单个 MsgID 的序号状态
+type msgSeqEntry struct {
+	seq      uint32
+	lastUsed time.Time
+}
+
+// MsgSeqAllocator 按 MsgID 分配递增的 msg_seq，保证同一条消息的多次回复不会被平台去重
+type MsgSeqAllocator struct {
+	mu   sync.Mutex
+	seqs map[string]*msgSeqEntry
+	ttl  time.Duration
+}
+
+// NewMsgSeqAllocator 创建序号分配器
+func NewMsgSeqAllocator(ttl time.Duration) *MsgSeqAllocator {
+	return &MsgSeqAllocator{
+		seqs: make(map[string]*msgSeqEntry),
+		ttl:  ttl,
+	}
+}
+
+// 全局序号分配器
+var msgSeqs = NewMsgSeqAllocator(msgSeqTTL)
+
+// Next 返回 msgID 的下一个序号（从 1 开始）；msgID 为空（主动消息）时返回 0
+func (a *MsgSeqAllocator) Next(msgID string) uint32 {
+	if msgID == "" {
+		return 0
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := time.Now()
+	a.pruneLocked(now)
+
+	entry, ok := a.seqs[msgID]
+	if !ok {
+		entry = &msgSeqEntry{}
+		a.seqs[msgID] = entry
+	}
+	entry.seq++
+	entry.lastUsed = now
+	return entry.seq
+}
+
+// pruneLocked 清理已超出被动回复有效期的记录（调用方需持有锁）
+func (a *MsgSeqAllocator) pruneLocked(now time.Time) {
+	for id, entry := range a.seqs {
+		if now.Sub(entry.lastUsed) > a.ttl {
+			delete(a.seqs, id)
+		}
+	}
+}
+
+// assignMsgSeq 为被动消息分配新的序号，每次发送（包括重试）前调用
+func assignMsgSeq(msg dto.APIMessage) {
+	if m, ok := msg.(*dto.MessageToCreate); ok && m.MsgID != "" {
+		m.MsgSeq = msgSeqs.Next(m.MsgID)
+		m.Timestamp = time.Now().UnixMilli()
+	}
+}
+
+// isMsgSeqDuplicated 判断发送失败是否因 msg_seq 重复被平台去重
+func isMsgSeqDuplicated(err error) bool {
+	var e *errs.Err
+	if !errors.As(err, &e) {
+		return false
+	}
+	var body struct {
+		Code int `json:"code"`
+	}
+	if jsonErr := json.Unmarshal([]byte(e.Text()), &body); jsonErr != nil {
+		return false
+	}
+	return body.Code == codeMsgSeqDuplicated
+}
@@ -5,8 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
-	"os"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 
@@ -27,9 +27,11 @@ type Processor struct {
 
 // ProcessChannelMessage is a function to process message
 func (p Processor) ProcessChannelMessage(input string, data *dto.WSATMessageData) error {
-	msg := generateDemoMessage(input, dto.Message(*data))
-	if err := p.sendChannelReply(context.Background(), data.ChannelID, msg); err != nil {
-		_ = p.sendChannelReply(context.Background(), data.GroupID, genErrMessage(dto.Message(*data), err))
+	msgBase := dto.Message(*data)
+	ctx := context.Background()
+	msg := generateDemoMessage(input, msgBase)
+	if err := p.sendChannelReply(ctx, data.ChannelID, msg); err != nil {
+		_ = replyError(ctx, NewChannelReplier(p, data.ChannelID, msgBase), err)
 	}
 	return nil
 }
@@ -72,18 +74,6 @@ func (p Processor) ProcessInlineSearch(interaction *dto.WSInteractionData) error
 	return nil
 }
 
-func genErrMessage(data dto.Message, err error) *dto.MessageToCreate {
-	return &dto.MessageToCreate{
-		Timestamp: time.Now().UnixMilli(),
-		Content:   fmt.Sprintf("处理异常:%v", err),
-		MessageReference: &dto.MessageReference{
-			// 引用这条消息
-			MessageID:             data.ID,
-			IgnoreGetMessageError: true,
-		},
-		MsgID: data.ID,
-	}
-}
 func createMessage(data dto.Message, msg string) *dto.MessageToCreate {
 	return &dto.MessageToCreate{
 		Timestamp: time.Now().UnixMilli(),
@@ -96,30 +86,18 @@ func createMessage(data dto.Message, msg string) *dto.MessageToCreate {
 		MsgID: data.ID,
 	}
 }
-func createRichMessage(data dto.Message, msg string, msgseq ...int) *dto.MessageToCreate {
-	msgSeq := uint32(1)
-	if len(msgseq) > 0 {
-		msgSeq = uint32(msgseq[0])
-	}
+
+// createRichMessage 创建富媒体消息，msg_seq 在发送时由 msgSeqs 分配
+func createRichMessage(data dto.Message, msg string) *dto.MessageToCreate {
 	m := &dto.MessageToCreate{
 		Timestamp: time.Now().UnixMilli(),
 		MsgType:   dto.RichMediaMsg,
 		Content:   fmt.Sprint(msg),
 		Media:     &dto.MediaInfo{},
 		MsgID:     data.ID,
-		MsgSeq:    msgSeq,
 	}
 	return m
 }
-func createImgMessage(data dto.Message, picContent []byte) *dto.RichMediaMessage {
-	return &dto.RichMediaMessage{
-		FileType:   1,
-		SrvSendMsg: false,
-		// Content:  fmt.Sprintf("data:image/png;base64,%s", base64.StdEncoding.EncodeToString(picContent)),
-		URL:     "https://apexlegendsstatus.com/assets/maps/Olympus.png",
-		Content: "测试",
-	}
-}
 
 // 定义命令关键词
 const (
@@ -145,33 +123,36 @@ func isCommandMatch(input string, cmdLists ...[]string) bool {
 }
 
 func parseEAIDFromInput(input string) string {
+	input = strings.TrimSpace(input)
+	if strings.HasPrefix(strings.ToLower(input), strings.ToLower(cmdPrefix)) {
+		// 兼容“/a 查询 xxx”这类前缀后带空格的写法
+		input = strings.TrimSpace(input[len(cmdPrefix):])
+	}
 	parts := strings.SplitN(input, " ", 2)
 	if len(parts) > 1 {
 		return strings.TrimSpace(parts[1])
 	}
 	return ""
 }
+
+// parseArgs 返回指令关键词之后的参数列表
+func parseArgs(input string) []string {
+	return strings.Fields(parseEAIDFromInput(input))
+}
+
 func normalizeInput(input string) string {
 	s := strings.TrimSpace(strings.ToLower(input))
 	s = regexp.MustCompile(`\s+`).ReplaceAllString(s, " ")
 	return s
 }
-func sendText(p Processor, isGroup bool, groupID string, userID string, msg dto.APIMessage) error {
-	if isGroup {
-		return p.sendGroupReply(context.Background(), groupID, msg)
-	}
-	return p.sendC2CReply(context.Background(), userID, msg)
-}
-func handleBind(p Processor, qqUser *dto.User, EAID string, base dto.Message, isGroup bool, groupID string, userID string) error {
+func handleBind(ctx context.Context, r Replier, qqUser *dto.User, EAID string) error {
 	if EAID == "" {
-		if isGroup {
-			return sendText(p, true, groupID, "", createMessage(base, "请提供有效的 EAID（必须为EA平台中的用户名，不可使用Steam名称）\n格式为 /a绑定 <EAID>\n例如如：/a绑定 MDY_KaLe"))
-		}
-		return sendText(p, false, "", userID, createMessage(base, "请提供有效的 EAID，格式为 /a绑定 <EAID>"))
+		return r.Text(ctx, "请提供有效的 EAID（必须为EA平台中的用户名，不可使用Steam名称）\n格式为 /a绑定 <EAID>\n例如如：/a绑定 MDY_KaLe")
 	}
-	player, err := apexapi.GetPlayerData(context.Background(), EAID)
+	player, err := apexapi.GetPlayerData(ctx, EAID)
 	if err != nil {
-		return sendText(p, isGroup, groupID, userID, createMessage(base, fmt.Sprint("绑定失败，查询信息时发送错误\n", err)))
+		botlog.Warnf("绑定 %s 时查询玩家数据失败: %v", EAID, err)
+		return r.Text(ctx, "绑定失败，"+apexapi.UserMessage(err))
 	}
 
 	rankScore := int(player.Global.Rank.RankScore)
@@ -186,9 +167,10 @@ func handleBind(p Processor, qqUser *dto.User, EAID string, base dto.Message, is
 	}
 	apexapi.Players.Set(qqUser.ID, bindingData)
 	if err := apexapi.Players.SaveBindingRecords(); err != nil {
-		return sendText(p, isGroup, groupID, userID, createMessage(base, fmt.Sprintf("保存绑定记录失败：%v", err)))
+		botlog.Errorf("保存绑定记录失败: %v", err)
+		return r.Text(ctx, "保存绑定记录失败，请稍后再试吧")
 	}
-	return sendText(p, isGroup, groupID, userID, createMessage(base, fmt.Sprintf("绑定成功！您的 EAID 是 %s", EAID)))
+	return r.Text(ctx, fmt.Sprintf("绑定成功！您的 EAID 是 %s", EAID))
 }
 func requireEAIDOrBinding(userID string, EAID string) (string, bool, int, time.Time, bool) {
 	if EAID != "" {
@@ -200,30 +182,50 @@ func requireEAIDOrBinding(userID string, EAID string) (string, bool, int, time.T
 	}
 	return bindingData.EAID, true, bindingData.LastRankScore, bindingData.LastUpdateTime, true
 }
-func handlePlayerQuery(p Processor, qqUser *dto.User, EAID string, base dto.Message, isGroup bool, groupID string, userID string) error {
+
+// parsePlayerQueryArgs 解析查询指令参数：[EAID] [传奇 <名称>]
+func parsePlayerQueryArgs(args []string) (eaid string, legend string) {
+	for i, arg := range args {
+		// 精确匹配，避免以 legend 开头的 EAID 被误判
+		if slices.Contains(legendCmds, strings.ToLower(arg)) {
+			return strings.Join(args[:i], " "), strings.Join(args[i+1:], " ")
+		}
+	}
+	return strings.Join(args, " "), ""
+}
+
+// handlePlayerQuery 查询玩家数据，legend 不为空时仅展示该传奇的数据
+func handlePlayerQuery(ctx context.Context, r Replier, qqUser *dto.User, EAID string, legend string) error {
 	eaid, bind, lastScore, lastUpdateTime, ok := requireEAIDOrBinding(qqUser.ID, EAID)
 	if !ok {
-		if isGroup {
-			return sendText(p, true, groupID, "", createMessage(base, "您尚未绑定 EAID，请使用 /a绑定 <EAID> 进行绑定"))
-		}
-		return sendText(p, false, "", userID, createMessage(base, "您尚未绑定 EAID，请使用 /a绑定 <EAID> 进行绑定"))
+		return r.Text(ctx, "您尚未绑定 EAID，请使用 /a绑定 <EAID> 进行绑定")
 	}
-	player, err := apexapi.GetPlayerData(context.Background(), eaid)
+	player, err := apexapi.GetPlayerData(ctx, eaid)
 	if err != nil {
-		return sendText(p, isGroup, groupID, userID, genErrMessage(base, err))
+		return replyError(ctx, r, err)
 	}
 	if player == nil {
-		return sendText(p, isGroup, groupID, userID, genErrMessage(base, fmt.Errorf("获取到空的玩家数据")))
+		return replyError(ctx, r, fmt.Errorf("获取到空的玩家数据"))
 	}
 
 	var msg string
-	if bind {
-		msg = apexapi.FormatPlayerData(player, apexapi.DisplayChangedOption{
+	if legend != "" {
+		name, ok := player.FindLegend(legend)
+		if !ok {
+			if !player.HasAllLegends() {
+				return r.Text(ctx, fmt.Sprintf("当前数据来源只提供所选传奇（%s）的数据，无法查询：%s\n（查询全部传奇需要管理员在配置中填写 apitoken）",
+					apexapi.GetLegendName(player.Legends.Selected.LegendName), legend))
+			}
+			return r.Text(ctx, fmt.Sprintf("未找到传奇：%s", legend))
+		}
+		msg = apexapi.FormatLegendData(player, name)
+	} else if bind {
+		msg = apexapi.FormatPlayerData(player, apexapi.GetPredatorForPlayer(player), apexapi.DisplayChangedOption{
 			LastScore: lastScore,
 			LastTime:  lastUpdateTime,
 		})
 	} else {
-		msg = apexapi.FormatPlayerData(player)
+		msg = apexapi.FormatPlayerData(player, apexapi.GetPredatorForPlayer(player))
 	}
 
 	if bind {
@@ -232,105 +234,242 @@ func handlePlayerQuery(p Processor, qqUser *dto.User, EAID string, base dto.Mess
 			bindingData.LastRankScore = rank
 			bindingData.LastUpdateTime = time.Now()
 			apexapi.Players.Set(qqUser.ID, bindingData)
+			if rank != lastScore {
+				if err := apexapi.RankHistory.Record(qqUser.ID, rank, bindingData.LastUpdateTime); err != nil {
+					botlog.Warnf("%v", err)
+				}
+			}
 		}
 	}
-	return sendText(p, isGroup, groupID, userID, createMessage(base, msg))
+	return r.Text(ctx, msg)
+}
+
+// handleServer 回复区服对照表，keyword 不为空时按区服代码、中文名或英文名筛选
+func handleServer(ctx context.Context, r Replier, keyword string) error {
+	servers, err := apexapi.GetServerRegions()
+	if err != nil {
+		return replyError(ctx, r, err)
+	}
+	servers = apexapi.FilterServerRegions(servers, keyword)
+	if len(servers) == 0 {
+		return r.Text(ctx, fmt.Sprintf("未找到与“%s”匹配的区服", keyword))
+	}
+
+	img, err := apexapi.RenderServerImage(servers)
+	if err != nil {
+		return replyError(ctx, r, err)
+	}
+	if err := r.Image(ctx, img, ""); err != nil {
+		botlog.Errorf("发送区服图片失败: %v", err)
+	}
+	return nil
+}
+
+// handleServerStatus 回复各区域服务器的运行状态图片
+func handleServerStatus(ctx context.Context, r Replier) error {
+	status, err := apexapi.GetServerStatus(ctx)
+	if err != nil {
+		return replyError(ctx, r, err)
+	}
+	img, err := apexapi.RenderServerStatusImage(status)
+	if err != nil {
+		return replyError(ctx, r, err)
+	}
+	if err := r.Image(ctx, img, apexapi.FormatServerStatusSummary(status)); err != nil {
+		botlog.Errorf("发送服务器状态图片失败: %v", err)
+	}
+	return nil
+}
+
+// handleCrafting 回复当前制造器轮换图片
+func handleCrafting(ctx context.Context, r Replier) error {
+	img, err := apexapi.GetCraftingImage()
+	if err != nil {
+		return replyError(ctx, r, err)
+	}
+	if err := r.Image(ctx, img, ""); err != nil {
+		botlog.Errorf("发送制造器图片失败: %v", err)
+	}
+	return nil
+}
+
+// handlePredator 回复各平台的排位猎杀线
+func handlePredator(ctx context.Context, r Replier) error {
+	predator, err := apexapi.GetPredator()
+	if err != nil {
+		return replyError(ctx, r, err)
+	}
+	return r.Text(ctx, apexapi.FormatPredator(predator))
 }
+
+// handleSeason 回复当前赛季与排位阶段信息
+func handleSeason(ctx context.Context, r Replier) error {
+	status, ok, err := apexapi.GetSeasonStatus()
+	if err != nil {
+		return replyError(ctx, r, err)
+	}
+	if !ok {
+		return r.Text(ctx, "暂无当前赛季信息，请联系管理员更新赛季日历")
+	}
+	return r.Text(ctx, apexapi.FormatSeasonStatus(status))
+}
+
 func helpMessage() string {
 	var b strings.Builder
 	b.WriteString("以下为指令示例（其中[]中的表示可选项）：\n")
 	b.WriteString("获取当前轮换地图：@机器人 [/a]地图\n")
+	b.WriteString("查看未来地图轮换：@机器人 [/a]地图 时间表，或指定时刻如@机器人 地图 21:00\n")
+	b.WriteString("设置地图展示的模式：@机器人 [/a]地图 模式 [模式...|全部]，如@机器人 地图 模式 匹配 排位\n")
 	b.WriteString("绑定/换绑EA账号：@机器人 [/a]绑定 EAID,如@机器人 绑定 kasaa\n")
 	b.WriteString("查询绑定的EA账号数据：@机器人 [/a]查询\n")
-	b.WriteString("获取区服对应中英文对照：@机器人 [/a]区服\n")
+	b.WriteString("查询单个传奇的数据：@机器人 [/a]查询 [EAID] 传奇 名称，如@机器人 查询 传奇 恶灵\n")
+	b.WriteString("对比两名玩家的数据：@机器人 [/a]对比 EAID1 [EAID2]，只填一个时与自己绑定的账号对比，也可@已绑定的群成员\n")
+	b.WriteString("查看在线状态：@机器人 [/a]在线 [EAID]，使用@机器人 在线 群 查看本群已绑定成员的在线情况\n")
+	b.WriteString("发起组队：@机器人 [/a]组队 [模式] [段位]，如@机器人 组队 排位 钻石；@机器人 组队 列表 查看招募中的队伍\n")
+	b.WriteString("加入组队：@机器人 [/a]加入 [编号]，满3人后自动@全部队员\n")
+	b.WriteString("查看制造器轮换：@机器人 [/a]制造\n")
+	b.WriteString("查看排位猎杀线：@机器人 [/a]猎杀\n")
+	b.WriteString("查看/订阅新闻与更新公告：@机器人 [/a]新闻 [订阅|取消订阅]\n")
+	b.WriteString("查看赛季与排位结束时间：@机器人 [/a]赛季\n")
+	b.WriteString("开启/关闭本群段位播报：@机器人 [/a]播报 [开启|关闭]\n")
+	b.WriteString("查看服务器运行状态（登录、匹配、跨平台）：@机器人 [/a]服务器状态\n")
+	b.WriteString("获取区服对应中英文对照：@机器人 [/a]区服 [关键字]，如@机器人 区服 香港\n")
 	return b.String()
 }
 
+// 命令关键词
+var (
+	mapCmds      = []string{"地图", "map"}
+	playerCmds   = []string{"查询", "player"}
+	bindCmds     = []string{"绑定", "bind"}
+	serverCmds   = []string{"区服", "server"}
+	helpCmds     = []string{"帮助", "help"}
+	seasonCmds   = []string{"赛季", "season"}
+	announceCmds = []string{"播报", "announce"}
+	legendCmds   = []string{"传奇", "legend"}
+	compareCmds  = []string{"对比", "compare"}
+	onlineCmds   = []string{"在线", "online"}
+	lfgCmds      = []string{"组队", "lfg"}
+	joinCmds     = []string{"加入", "join"}
+	craftingCmds = []string{"制造", "crafting"}
+	predatorCmds = []string{"猎杀", "predator"}
+	newsCmds     = []string{"新闻", "news"}
+	statusCmds   = []string{"服务器状态", "status"}
+)
+
+// cmdEnv 指令的执行环境
+type cmdEnv struct {
+	r        Replier
+	user     *dto.User
+	groupID  string      // 群聊时为群 openid，单聊时为空
+	mentions []*dto.User // 消息中@的其他用户（不含机器人）
+}
+
+// settingScope 保存设置使用的范围 ID：群聊为群 openid，单聊为用户 openid
+func (e cmdEnv) settingScope() string {
+	if e.groupID != "" {
+		return e.groupID
+	}
+	if e.user != nil {
+		return e.user.ID
+	}
+	return ""
+}
+
+// handleCommand 分发群与 C2C 共用的指令，未匹配任何指令时 handled 为 false
+func (p Processor) handleCommand(ctx context.Context, env cmdEnv, input string) (handled bool, err error) {
+	r := env.r
+	// 记录匹配到的指令，用于统计调用次数
+	var command string
+	match := func(cmds []string) bool {
+		if !isCommandMatch(input, cmds) {
+			return false
+		}
+		command = cmds[len(cmds)-1]
+		return true
+	}
+	defer func() {
+		if handled {
+			recordCommand(env, command)
+		}
+	}()
+
+	switch {
+	case match(bindCmds):
+		rememberGroupMember(env)
+		_ = handleBind(ctx, r, env.user, parseEAIDFromInput(input))
+	case match(mapCmds):
+		return true, handleMapCommand(ctx, env, parseArgs(input))
+	case match(statusCmds):
+		return true, handleServerStatus(ctx, r)
+	case match(serverCmds):
+		return true, handleServer(ctx, r, parseEAIDFromInput(input))
+	case match(playerCmds):
+		rememberGroupMember(env)
+		eaid, legend := parsePlayerQueryArgs(parseArgs(input))
+		_ = handlePlayerQuery(ctx, r, env.user, eaid, legend)
+	case match(craftingCmds):
+		return true, handleCrafting(ctx, r)
+	case match(predatorCmds):
+		return true, handlePredator(ctx, r)
+	case match(newsCmds):
+		return true, handleNewsCommand(ctx, env, parseArgs(input))
+	case match(seasonCmds):
+		_ = handleSeason(ctx, r)
+	case match(compareCmds):
+		rememberGroupMember(env)
+		return true, handleCompare(ctx, env, parseArgs(input))
+	case match(onlineCmds):
+		rememberGroupMember(env)
+		return true, handleOnline(ctx, env, parseArgs(input))
+	case match(lfgCmds):
+		rememberGroupMember(env)
+		return true, handleLFGCreate(ctx, env, parseArgs(input))
+	case match(joinCmds):
+		rememberGroupMember(env)
+		return true, handleLFGJoin(ctx, env, parseArgs(input))
+	case match(announceCmds):
+		return true, handleAnnounceCommand(ctx, env, parseArgs(input))
+	case match(helpCmds):
+		_ = r.Text(ctx, helpMessage())
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
 // ProcessGroupMessage 回复群消息
 func (p Processor) ProcessGroupMessage(input string, data *dto.WSGroupATMessageData) error {
 	input = normalizeInput(input)
-	var (
-		mapCmds    = []string{"地图", "map"}
-		playerCmds = []string{"查询", "player"}
-		bindCmds   = []string{"绑定", "bind"}
-		serverCmds = []string{"区服", "server"}
-		helpCmds   = []string{"帮助", "help"}
-	)
 	var qqUser *dto.User
 	if data.Author != nil && data.Author.ID != "" {
 		qqUser = data.Author
 	}
 	msgBase := dto.Message(*data)
+	ctx := context.Background()
+	r := NewGroupReplier(p, data.GroupID, msgBase)
 
-	if isCommandMatch(input, bindCmds) {
-		EAID := parseEAIDFromInput(input)
-		_ = handleBind(p, qqUser, EAID, msgBase, true, data.GroupID, "")
-		return nil
-	}
-
-	if isCommandMatch(input, mapCmds) {
-		mapResultPath, err := apexapi.GetMapResult()
-		if err != nil {
-			botlog.Warnf("获取地图轮换失败: %v", err)
-			return err
-		}
-
-		err, shouldReturn := p.GetImgAndSendToGroup(mapResultPath, msgBase, data)
-		if shouldReturn {
-			return err
+	var mentions []*dto.User
+	for _, u := range data.Mentions {
+		if u != nil && u.ID != "" && !u.Bot {
+			mentions = append(mentions, u)
 		}
-
-		return nil
 	}
-	if isCommandMatch(input, serverCmds) {
-		err, shouldReturn := p.GetImgAndSendToGroup("asset/Static/Server.png", msgBase, data)
-		if shouldReturn {
-			return err
-		}
 
-		return nil
-	}
-
-	if isCommandMatch(input, playerCmds) {
-		EAID := parseEAIDFromInput(input)
-		_ = handlePlayerQuery(p, qqUser, EAID, msgBase, true, data.GroupID, "")
-		return nil
-	}
-	if isCommandMatch(input, helpCmds) {
-		replyMsg := createMessage(msgBase, helpMessage())
-		_ = p.sendGroupReply(context.Background(), data.GroupID, replyMsg)
-		return nil
+	env := cmdEnv{r: r, user: qqUser, groupID: data.GroupID, mentions: mentions}
+	if handled, err := p.handleCommand(ctx, env, input); handled {
+		return err
 	}
 
 	msg := generateDemoMessage(input, msgBase)
-	if err := p.sendGroupReply(context.Background(), data.GroupID, msg); err != nil {
+	if err := p.sendGroupReply(ctx, data.GroupID, msg); err != nil {
 		log.Printf("发送默认回复失败: %v", err)
-		_ = p.sendGroupReply(context.Background(), data.GroupID, genErrMessage(msgBase, err))
+		_ = replyError(ctx, r, err)
 	}
 
 	return nil
 }
 
-func (p Processor) GetImgAndSendToGroup(mapResultPath string, msgBase dto.Message, data *dto.WSGroupATMessageData) (error, bool) {
-	qrContent, err := os.ReadFile(mapResultPath)
-	if err != nil {
-		botlog.Warnf("读取地图图片失败: %v", err)
-		replyMsg := createMessage(msgBase, "读取地图图片失败，请反馈至开发人员")
-		if sendErr := p.sendGroupReply(context.Background(), data.GroupID, replyMsg); sendErr != nil {
-			botlog.Warnf("发送错误消息失败: %v", sendErr)
-		}
-		return nil, true
-	}
-
-	imgRichMsg := createRichMessage(msgBase, "")
-	err = p.sendGroupImgDataReply(context.Background(), data.GroupID, qrContent, imgRichMsg)
-	if err != nil {
-		botlog.Errorf("发送地图图片失败: %v", err)
-		return nil, true
-	}
-	return nil, false
-}
-
 // ProcessC2CMessage 回复C2C消息
 func (p Processor) ProcessC2CMessage(input string, data *dto.WSC2CMessageData) error {
 	input = normalizeInput(input)
@@ -340,27 +479,17 @@ func (p Processor) ProcessC2CMessage(input string, data *dto.WSC2CMessageData) e
 		userID = data.Author.ID
 		qqUser = data.Author
 	}
-	var (
-		playerCmds = []string{"查询", "player"}
-		bindCmds   = []string{"绑定", "bind"}
-	)
-
 	msgBase := dto.Message(*data)
+	ctx := context.Background()
+	r := NewC2CReplier(p, userID, msgBase)
 
-	if isCommandMatch(input, bindCmds) {
-		EAID := parseEAIDFromInput(input)
-		_ = handleBind(p, qqUser, EAID, msgBase, false, "", userID)
-		return nil
+	if handled, err := p.handleCommand(ctx, cmdEnv{r: r, user: qqUser}, input); handled {
+		return err
 	}
 
-	if isCommandMatch(input, playerCmds) {
-		EAID := parseEAIDFromInput(input)
-		_ = handlePlayerQuery(p, qqUser, EAID, msgBase, false, "", userID)
-		return nil
-	}
-	msg := generateDemoMessage(input, dto.Message(*data))
-	if err := p.sendC2CReply(context.Background(), userID, msg); err != nil {
-		_ = p.sendC2CReply(context.Background(), userID, genErrMessage(dto.Message(*data), err))
+	msg := generateDemoMessage(input, msgBase)
+	if err := p.sendC2CReply(ctx, userID, msg); err != nil {
+		_ = replyError(ctx, r, err)
 	}
 	return nil
 }
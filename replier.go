@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/newton-miku/apexQQbot/apexapi"
+	"github.com/tencent-connect/botgo/dto"
+	botlog "github.com/tencent-connect/botgo/log"
+)
+
+// 富媒体文件类型
+const (
+	fileTypeImage = 1
+	fileTypeVideo = 2
+	fileTypeVoice = 3
+	fileTypeFile  = 4
+)
+
+// Replier 统一的消息回复接口，屏蔽群、C2C 与频道之间的差异
+type Replier interface {
+	// Text 回复文本消息
+	Text(ctx context.Context, content string) error
+	// Image 上传图片数据并回复，content 为随图片发送的文字（可为空）
+	Image(ctx context.Context, data []byte, content string) error
+	// ImageURL 通过图片链接回复
+	ImageURL(ctx context.Context, url string, content string) error
+	// File 上传富媒体文件并回复，fileType 取值见 fileType* 常量
+	File(ctx context.Context, fileType int, data []byte, content string) error
+}
+
+// replyError 回复处理异常：完整错误只写入日志，用户只会看到对应类别的提示
+func replyError(ctx context.Context, r Replier, err error) error {
+	botlog.Warnf("处理指令失败: %v", err)
+	return r.Text(ctx, apexapi.UserMessage(err))
+}
+
+// ============ 群 ============
+
+// groupReplier 群消息回复
+type groupReplier struct {
+	p       Processor
+	groupID string
+	base    dto.Message
+}
+
+// NewGroupReplier 创建回复指定群消息的 Replier
+func NewGroupReplier(p Processor, groupID string, base dto.Message) Replier {
+	return groupReplier{p: p, groupID: groupID, base: base}
+}
+
+func (r groupReplier) Text(ctx context.Context, content string) error {
+	return r.p.sendGroupReply(ctx, r.groupID, createMessage(r.base, content))
+}
+
+func (r groupReplier) Image(ctx context.Context, data []byte, content string) error {
+	return r.File(ctx, fileTypeImage, data, content)
+}
+
+func (r groupReplier) ImageURL(ctx context.Context, url string, content string) error {
+	media, err := r.p.uploadGroupMedia(ctx, r.groupID, RichMediaUpload{FileType: fileTypeImage, URL: url})
+	if err != nil {
+		return err
+	}
+	return r.sendMedia(ctx, media, content)
+}
+
+func (r groupReplier) File(ctx context.Context, fileType int, data []byte, content string) error {
+	media, err := r.p.uploadGroupData(ctx, r.groupID, fileType, data)
+	if err != nil {
+		return err
+	}
+	if err := r.sendMedia(ctx, media, content); err != nil {
+		// file_info 可能已提前失效，丢弃缓存以便下次重新上传
+		uploadedMedia.Remove(media)
+		return err
+	}
+	return nil
+}
+
+func (r groupReplier) sendMedia(ctx context.Context, media *dto.MediaInfo, content string) error {
+	msg := createRichMessage(r.base, content)
+	msg.Media = media
+	return r.p.sendGroupReply(ctx, r.groupID, msg)
+}
+
+// ============ C2C ============
+
+// c2cReplier 单聊消息回复
+type c2cReplier struct {
+	p      Processor
+	userID string
+	base   dto.Message
+}
+
+// NewC2CReplier 创建回复指定用户单聊消息的 Replier
+func NewC2CReplier(p Processor, userID string, base dto.Message) Replier {
+	return c2cReplier{p: p, userID: userID, base: base}
+}
+
+func (r c2cReplier) Text(ctx context.Context, content string) error {
+	return r.p.sendC2CReply(ctx, r.userID, createMessage(r.base, content))
+}
+
+func (r c2cReplier) Image(ctx context.Context, data []byte, content string) error {
+	return r.File(ctx, fileTypeImage, data, content)
+}
+
+func (r c2cReplier) ImageURL(ctx context.Context, url string, content string) error {
+	media, err := r.p.uploadC2CMedia(ctx, r.userID, RichMediaUpload{FileType: fileTypeImage, URL: url})
+	if err != nil {
+		return err
+	}
+	return r.sendMedia(ctx, media, content)
+}
+
+func (r c2cReplier) File(ctx context.Context, fileType int, data []byte, content string) error {
+	media, err := r.p.uploadC2CData(ctx, r.userID, fileType, data)
+	if err != nil {
+		return err
+	}
+	if err := r.sendMedia(ctx, media, content); err != nil {
+		// file_info 可能已提前失效，丢弃缓存以便下次重新上传
+		uploadedMedia.Remove(media)
+		return err
+	}
+	return nil
+}
+
+func (r c2cReplier) sendMedia(ctx context.Context, media *dto.MediaInfo, content string) error {
+	msg := createRichMessage(r.base, content)
+	msg.Media = media
+	return r.p.sendC2CReply(ctx, r.userID, msg)
+}
+
+// ============ 频道 ============
+
+// channelReplier 子频道消息回复
+type channelReplier struct {
+	p         Processor
+	channelID string
+	base      dto.Message
+}
+
+// NewChannelReplier 创建回复指定子频道消息的 Replier
+func NewChannelReplier(p Processor, channelID string, base dto.Message) Replier {
+	return channelReplier{p: p, channelID: channelID, base: base}
+}
+
+func (r channelReplier) Text(ctx context.Context, content string) error {
+	return r.p.sendChannelReply(ctx, r.channelID, createMessage(r.base, content))
+}
+
+func (r channelReplier) Image(ctx context.Context, data []byte, content string) error {
+	return r.p.sendChannelImgDataReply(ctx, r.channelID, r.base.ID, data, content)
+}
+
+func (r channelReplier) ImageURL(ctx context.Context, url string, content string) error {
+	msg := createMessage(r.base, content)
+	msg.Image = url
+	return r.p.sendChannelReply(ctx, r.channelID, msg)
+}
+
+func (r channelReplier) File(ctx context.Context, fileType int, data []byte, content string) error {
+	if fileType == fileTypeImage {
+		return r.Image(ctx, data, content)
+	}
+	return fmt.Errorf("频道暂不支持发送该类型文件: %d", fileType)
+}
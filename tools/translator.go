@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -67,9 +68,20 @@ func (t *Translator) loadConfig() error {
 	t.transMap = tempMap
 
 	// 构建strings.Replacer的参数（格式：key1, value1, key2, value2...）
+	// Replacer 按参数顺序决定优先级，长关键字优先，避免 arenasRanked 被 arenas 抢先替换
+	keys := make([]string, 0, len(tempMap))
+	for k := range tempMap {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) > len(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
 	replacerArgs := make([]string, 0, len(tempMap)*2)
-	for k, v := range tempMap {
-		replacerArgs = append(replacerArgs, k, v)
+	for _, k := range keys {
+		replacerArgs = append(replacerArgs, k, tempMap[k])
 	}
 	// 预编译替换器
 	t.replacer = strings.NewReplacer(replacerArgs...)
@@ -124,6 +136,29 @@ func (t *Translator) Translate(input string) string {
 	return t.replacer.Replace(input)
 }
 
+// Lookup 精确查找关键字对应的翻译
+func (t *Translator) Lookup(key string) (string, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	v, ok := t.transMap[key]
+	return v, ok
+}
+
+// ReverseLookup 通过翻译值反查关键字（忽略大小写与首尾空格）
+func (t *Translator) ReverseLookup(value string) (string, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	value = strings.TrimSpace(value)
+	for k, v := range t.transMap {
+		if strings.EqualFold(v, value) {
+			return k, true
+		}
+	}
+	return "", false
+}
+
 // Close 关闭监听器（资源释放）
 func (t *Translator) Close() error {
 	return t.watcher.Close()